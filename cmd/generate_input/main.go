@@ -0,0 +1,294 @@
+// Command generate_input compiles the ECDSA, message, WebAuthn, batch, membership, nullifier or threshold circuit, runs the Groth16 or PLONK
+// setup, checks a sample proof and writes the artifacts used by the cgo library,
+// along with the Solidity verifier and a Foundry fixture for the public variant.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time" // Added for performance timing
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+func main() {
+	circuitName := flag.String("circuit", string(zkecdsa.CircuitECDSA), "circuit: ecdsa, message (message hashed in-circuit), webauthn (WebAuthn assertion, challenge and public key public), batch (-batch-size signatures, commitment public), membership (signer in a key tree, message hash and root public), nullifier (key private, message hash, scope and nullifier public) or threshold (-threshold of -signers keys signed, message hash and keys public)")
+	curveName := flag.String("curve", string(zkecdsa.P256), "signature curve: p256 or secp256k1")
+	backendName := flag.String("backend", string(zkecdsa.Groth16), "proof system: groth16 or plonk")
+	keyEncodingName := flag.String("key-encoding", string(zkecdsa.KeyCompressed), "proving key encoding: compressed (checked on load) or raw (about twice the size, loads without checks)")
+	srs := flag.String("srs", "", "PLONK only: KZG SRS file in canonical form (default: generate an unsafe test SRS)")
+	publicInputs := flag.Bool("public", false, "ECDSA and message only: make the message (hash) and public key public inputs")
+	maxMessageLen := flag.Int("max-message-len", zkecdsa.DefaultMaxMessageLen, "message circuit only: message capacity in bytes")
+	commit := flag.Bool("commit", false, "message and threshold circuits: make a commitment to the message or keys public instead of the message or keys")
+	batchSize := flag.Int("batch-size", zkecdsa.DefaultBatchSize, "batch circuit only: signatures per proof")
+	merkleDepth := flag.Int("merkle-depth", zkecdsa.DefaultMerkleDepth, "membership circuit only: depth of the key tree")
+	signers := flag.Int("signers", zkecdsa.DefaultThresholdSigners, "threshold circuit only: number of keys n")
+	threshold := flag.Int("threshold", zkecdsa.DefaultThreshold, "threshold circuit only: signatures required k")
+	publicThreshold := flag.Bool("public-threshold", false, "threshold circuit only: make k a public input instead of compiling it in")
+	scope := flag.String("scope", "gnark-playground", "nullifier circuit only: label of the sample scope, see zkecdsa.NewScope")
+	commitmentHash := flag.String("commitment-hash", string(zkecdsa.MiMC), "with -commit or the batch, membership and nullifier circuits: commitment, key tree or nullifier hash, mimc or poseidon2")
+	var paths zkecdsa.Paths
+	flag.StringVar(&paths.Dir, "dir", "", "artifact directory (default $"+zkecdsa.EnvArtifactDir+" or the working directory)")
+	flag.StringVar(&paths.R1CS, "r1cs", "", "constraint system output path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.R1CSFile+")")
+	flag.StringVar(&paths.ProvingKey, "pk", "", "proving key output path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.ProvingKeyFile+")")
+	flag.StringVar(&paths.VerifyingKey, "vk", "", "verifying key output path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.VerifyingKeyFile+")")
+	flag.StringVar(&paths.WitnessInput, "input", "", "sample witness input output path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.WitnessInputFile+")")
+	flag.StringVar(&paths.Manifest, "manifest", "", "manifest output path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.ManifestFile+")")
+	solidityPath := flag.String("solidity", "", "Solidity verifier output path, with -public (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.SolidityVerifierFile+")")
+	fixturePath := flag.String("fixture", "", "Foundry fixture output path, with -public (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.FixtureFile+")")
+	flag.Parse()
+
+	circuit, err := zkecdsa.ParseCircuitType(*circuitName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	curve, err := zkecdsa.ParseCurve(*curveName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	backend, err := zkecdsa.ParseBackend(*backendName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	keyEncoding, err := zkecdsa.ParseKeyEncoding(*keyEncodingName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	hash, err := zkecdsa.ParseFieldHash(*commitmentHash)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	paths.Circuit, paths.Curve, paths.BatchSize = circuit, curve, *batchSize
+	paths = paths.Resolve()
+
+	fmt.Printf("--- Generating %s circuit inputs and performing compliance check ---\n", circuit)
+
+	// 1. Off-circuit signature generation (to get inputs for the circuit)
+	var proveInput zkecdsa.Input
+	message := []byte("testing ECDSA with gnark-CGO")
+	switch circuit {
+	case zkecdsa.CircuitWebAuthn:
+		proveInput, err = zkecdsa.GenerateWebAuthnInput(curve)
+	case zkecdsa.CircuitMessage:
+		proveInput, err = zkecdsa.GenerateMessageInput(curve, message, *maxMessageLen, *commit, hash)
+	case zkecdsa.CircuitBatch:
+		proveInput, err = zkecdsa.GenerateBatchInput(curve, *batchSize, hash)
+	case zkecdsa.CircuitMembership:
+		proveInput, err = zkecdsa.GenerateMembershipInput(curve, message, *merkleDepth, hash)
+	case zkecdsa.CircuitNullifier:
+		proveInput, err = zkecdsa.GenerateNullifierInput(curve, message, zkecdsa.NewScope(*scope), hash)
+	case zkecdsa.CircuitThreshold:
+		proveInput, err = zkecdsa.GenerateThresholdInput(curve, message, *signers, *threshold, *publicThreshold, *commit, hash)
+	default:
+		proveInput, err = zkecdsa.GenerateInput(curve, message)
+	}
+	if err != nil {
+		fmt.Printf("Error generating off-circuit signature: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 2. Compile the circuit and perform the backend setup
+	fmt.Printf("Compiling %s %s circuit (public inputs: %t) and starting %s setup...\n", circuit, curve, *publicInputs || *commit || circuit == zkecdsa.CircuitWebAuthn || circuit == zkecdsa.CircuitBatch || circuit == zkecdsa.CircuitMembership || circuit == zkecdsa.CircuitNullifier || circuit == zkecdsa.CircuitThreshold, backend)
+	if backend == zkecdsa.Plonk && *srs == "" {
+		fmt.Println("WARNING: no -srs given, using an unsafe KZG SRS fit for testing only")
+	}
+	artifacts, err := zkecdsa.Setup(zkecdsa.Config{
+		Circuit:         circuit,
+		Curve:           curve,
+		Backend:         backend,
+		PublicInputs:    *publicInputs,
+		SRS:             *srs,
+		MaxMessageLen:   *maxMessageLen,
+		CommitMessage:   *commit,
+		BatchSize:       *batchSize,
+		MerkleDepth:     *merkleDepth,
+		Signers:         *signers,
+		Threshold:       *threshold,
+		PublicThreshold: *publicThreshold,
+		CommitKeys:      *commit,
+		CommitmentHash:  hash,
+	})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("BN254 circuit compiled with %d constraints\n", artifacts.CCS.GetNbConstraints())
+	fmt.Printf("Setup done.\n")
+
+	// 3. Perform a compliance check: Prove and Verify
+	fmt.Println("\n--- Performing compliance check (Prove & Verify within generate_input) ---")
+	proof, publicWitness, err := proveAndVerify(artifacts, proveInput)
+	if err != nil {
+		fmt.Printf("Compliance check: %v\n", err)
+		os.Exit(1)
+	}
+	if in, ok := proveInput.(*zkecdsa.NullifierInput); ok {
+		if err := checkNullifierStore(proof, artifacts.VK, in); err != nil {
+			fmt.Printf("Compliance check: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Println("Compliance check PASSED. Generated inputs are valid.")
+
+	// 4. Write outputs to files
+	artifacts.KeyEncoding = keyEncoding
+	if err := artifacts.Save(paths); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := zkecdsa.WriteInput(paths.WitnessInput, proveInput); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %s, %s, %s, %s, %s\n", paths.R1CS, paths.ProvingKey, paths.VerifyingKey, paths.WitnessInput, paths.Manifest)
+	fmt.Println("\nAll input files generated successfully for CGO wrapper.")
+
+	// 5. Export the on-chain verifier, with the compliance check proof as fixture
+	if artifacts.PublicInputs() {
+		if *solidityPath == "" {
+			*solidityPath = filepath.Join(paths.Namespace(), zkecdsa.SolidityVerifierFile)
+		}
+		if *fixturePath == "" {
+			*fixturePath = filepath.Join(paths.Namespace(), zkecdsa.FixtureFile)
+		}
+		if err := writeOnChainOutputs(artifacts, proof, publicWitness, *solidityPath, *fixturePath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %s, %s\n", *solidityPath, *fixturePath)
+	} else {
+		fmt.Println("Skipping the Solidity verifier: the private circuit has no public input to check on-chain (use -public).")
+	}
+
+	// 6. Test the ReadFromFile functionality
+	testReadFromFile(paths)
+
+	// 7. Compare the load times of both proving key encodings
+	if err := benchmarkKeyLoading(artifacts); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// proveAndVerify proves proveInput and verifies the proof, printing timings.
+func proveAndVerify(artifacts *zkecdsa.Artifacts, proveInput zkecdsa.Input) (zkecdsa.Proof, witness.Witness, error) {
+	// Prove
+	startProve := time.Now()
+	proof, publicWitness, err := zkecdsa.Prove(artifacts.CCS, artifacts.PK, proveInput)
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Printf("Proof generated (%.1fms).\n", time.Since(startProve).Seconds()*1000)
+
+	// Verify, against the public values of the input only when there are some
+	startVerify := time.Now()
+	if artifacts.PublicInputs() {
+		err = zkecdsa.VerifyWithPublicInputs(proof, artifacts.VK, proveInput)
+	} else {
+		err = zkecdsa.Verify(proof, artifacts.VK, publicWitness)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Printf("Verification SUCCEEDED (%.1fms)!\n", time.Since(startVerify).Seconds()*1000)
+	return proof, publicWitness, nil
+}
+
+// checkNullifierStore verifies the nullifier proof against a fresh store
+// twice, the second verification having to be rejected as a double use.
+func checkNullifierStore(proof zkecdsa.Proof, vk zkecdsa.VerifyingKey, in *zkecdsa.NullifierInput) error {
+	store := zkecdsa.NewMemoryNullifierStore()
+	if err := zkecdsa.VerifyNullifier(proof, vk, in, store); err != nil {
+		return err
+	}
+	if err := zkecdsa.VerifyNullifier(proof, vk, in, store); !errors.Is(err, zkecdsa.ErrNullifierUsed) {
+		return fmt.Errorf("nullifier reuse not detected: %v", err)
+	}
+	fmt.Printf("Nullifier %s rejected on reuse.\n", in.Nullifier)
+	return nil
+}
+
+// writeOnChainOutputs writes the Solidity verifier for the artifacts and the
+// Foundry fixture of proof.
+func writeOnChainOutputs(artifacts *zkecdsa.Artifacts, proof zkecdsa.Proof, publicWitness witness.Witness, solidityPath, fixturePath string) error {
+	if err := zkecdsa.WriteSolidityVerifier(solidityPath, artifacts.VK); err != nil {
+		return err
+	}
+	fixture, err := zkecdsa.NewFixture(proof, publicWitness)
+	if err != nil {
+		return err
+	}
+	return zkecdsa.WriteFixture(fixturePath, fixture)
+}
+
+// testReadFromFile reads the generated files back and performs a verification.
+func testReadFromFile(paths zkecdsa.Paths) {
+	fmt.Println("\n--- Testing ReadFromFile and re-verification ---")
+
+	startLoad := time.Now()
+	artifacts, err := zkecdsa.LoadArtifacts(paths)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Read %s artifacts (Constraints: %d, %s proving key, %.1fms)\n", artifacts.Backend, artifacts.CCS.GetNbConstraints(), artifacts.KeyEncoding, time.Since(startLoad).Seconds()*1000)
+
+	proveInput, err := zkecdsa.ReadCircuitInput(paths.Circuit, paths.WitnessInput)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Read %s\n", paths.WitnessInput)
+
+	fmt.Println("\n--- Proving and Verifying with loaded artifacts ---")
+	if _, _, err := proveAndVerify(artifacts, proveInput); err != nil {
+		fmt.Printf("Verification from loaded files: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("ReadFromFile test PASSED. Loaded artifacts are valid and functional.")
+}
+
+// benchmarkKeyLoading writes the proving key in each encoding to a temporary
+// directory and prints the time it takes to read it back.
+func benchmarkKeyLoading(artifacts *zkecdsa.Artifacts) error {
+	fmt.Println("\n--- Proving key load time by encoding ---")
+	dir, err := os.MkdirTemp("", "zkecdsa-pk-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var compressedTime time.Duration
+	for _, encoding := range []zkecdsa.KeyEncoding{zkecdsa.KeyCompressed, zkecdsa.KeyRaw} {
+		filename := filepath.Join(dir, string(encoding)+".bin")
+		if err := zkecdsa.WriteProvingKey(filename, artifacts.PK, encoding); err != nil {
+			return err
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		if _, _, err := zkecdsa.ReadProvingKey(filename, artifacts.Backend); err != nil {
+			return err
+		}
+		elapsed := time.Since(start)
+		fmt.Printf("%-10s %8.1f MB  loaded in %.1fms", encoding, float64(info.Size())/(1<<20), elapsed.Seconds()*1000)
+		if encoding == zkecdsa.KeyCompressed {
+			compressedTime = elapsed
+			fmt.Println()
+		} else {
+			fmt.Printf(" (%.1fx faster)\n", compressedTime.Seconds()/elapsed.Seconds())
+		}
+	}
+	return nil
+}
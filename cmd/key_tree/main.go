@@ -0,0 +1,106 @@
+// Command key_tree builds the Merkle tree of a list of public keys for the
+// membership circuit, writes its root, and turns a signature by one of the
+// keys into a membership witness input carrying the Merkle path.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// treeJSON is the description of the tree written with -out.
+type treeJSON struct {
+	Curve zkecdsa.Curve     `json:"curve"`
+	Hash  zkecdsa.FieldHash `json:"hash"`
+	Depth int               `json:"depth"`
+	Root  string            `json:"root"` // Hex string of the root
+	Keys  []string          `json:"keys"` // Hex strings of X || Y, in leaf order
+}
+
+func main() {
+	keysPath := flag.String("keys", "", "file listing the public keys: PEM PUBLIC KEY blocks and/or hex X || Y or SEC1 lines")
+	curveName := flag.String("curve", string(zkecdsa.P256), "signature curve: p256 or secp256k1")
+	hashName := flag.String("hash", string(zkecdsa.MiMC), "tree hash: mimc or poseidon2")
+	depth := flag.Int("depth", zkecdsa.DefaultMerkleDepth, "tree depth, as compiled into the membership circuit")
+	outPath := flag.String("out", "", "tree JSON output path (default: stdout)")
+	signedPath := flag.String("input", "", "ECDSA witness input signed by one of the keys, to turn into a membership input")
+	membershipPath := flag.String("membership-input", "membership_input.json", "with -input: membership witness input output path")
+	flag.Parse()
+
+	if *keysPath == "" {
+		fmt.Println("Error: -keys is required")
+		os.Exit(1)
+	}
+	curve, err := zkecdsa.ParseCurve(*curveName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	hash, err := zkecdsa.ParseFieldHash(*hashName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(*keysPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	keys, err := zkecdsa.ParsePublicKeys(curve, data)
+	if err != nil {
+		fmt.Printf("Error parsing %s: %v\n", *keysPath, err)
+		os.Exit(1)
+	}
+	tree, err := zkecdsa.NewKeyTree(hash, curve, *depth, keys)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := treeJSON{Curve: curve, Hash: hash, Depth: *depth, Root: hex.EncodeToString(tree.Root().FillBytes(make([]byte, 32)))}
+	for _, key := range keys {
+		point := make([]byte, 64)
+		key.X.FillBytes(point[:32])
+		key.Y.FillBytes(point[32:])
+		out.Keys = append(out.Keys, hex.EncodeToString(point))
+	}
+	treeData, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		fmt.Printf("Error marshaling tree JSON: %v\n", err)
+		os.Exit(1)
+	}
+	if *outPath == "" {
+		fmt.Println(string(treeData))
+	} else {
+		if err := os.WriteFile(*outPath, treeData, 0o644); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote the tree of %d keys (root %s) to %s\n", len(keys), out.Root, *outPath)
+	}
+
+	if *signedPath == "" {
+		return
+	}
+	signed, err := zkecdsa.ReadInput(*signedPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	membership, err := tree.MembershipInput(signed)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := zkecdsa.WriteInput(*membershipPath, membership); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote the membership input of key %d to %s\n", membership.Index, *membershipPath)
+}
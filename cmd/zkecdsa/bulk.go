@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// maxRecordLen bounds a line of the input, batch and threshold records
+// listing many signatures.
+const maxRecordLen = 16 << 20
+
+// bulkResult is a line of the output of bulk: the proof of a record, or the
+// error it failed with.
+type bulkResult struct {
+	Line          int             `json:"line"`         // Line of the record in the input, from 1
+	ID            json.RawMessage `json:"id,omitempty"` // "id" member of the record, if any
+	Proof         string          `json:"proof,omitempty"`
+	PublicWitness string          `json:"publicWitness,omitempty"`
+	ProveMs       int64           `json:"proveMs"`
+	Error         string          `json:"error,omitempty"`
+	ErrorKind     string          `json:"errorKind,omitempty"` // See zkecdsa.ErrorKind
+}
+
+// key identifies the record of the result across runs: its normalized id if
+// it has one, else its line.
+func (r *bulkResult) key() string {
+	if id := normalizeID(r.ID); id != nil {
+		return "id:" + string(id)
+	}
+	return "line:" + strconv.Itoa(r.Line)
+}
+
+// bulkRecord is a line of the input to prove.
+type bulkRecord struct {
+	line int
+	data []byte
+}
+
+// bulkReport sums up a bulk run.
+type bulkReport struct {
+	Circuit     zkecdsa.CircuitType `json:"circuit"`
+	Curve       zkecdsa.Curve       `json:"curve"`
+	Backend     zkecdsa.Backend     `json:"backend"`
+	Output      string              `json:"output"`
+	Records     int                 `json:"records"` // Records read, skipped ones included
+	Proved      int                 `json:"proved"`
+	Failed      int                 `json:"failed"`
+	Skipped     int                 `json:"skipped"`     // Records with a result from an earlier run
+	Interrupted bool                `json:"interrupted"` // Stopped by a signal before the end of the input
+	LoadMs      int64               `json:"loadMs"`
+	ElapsedMs   int64               `json:"elapsedMs"`
+}
+
+func (r *bulkReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Loaded the %s %s %s artifacts in %dms\n", r.Circuit, r.Curve, r.Backend, r.LoadMs)
+	fmt.Fprintf(w, "%d records: %d proved, %d failed, %d skipped in %dms\n", r.Records, r.Proved, r.Failed, r.Skipped, r.ElapsedMs)
+	if r.Interrupted {
+		fmt.Fprintln(w, "Interrupted: run the same command again to resume.")
+	}
+	fmt.Fprintf(w, "Results in %s\n", r.Output)
+}
+
+// errBulkIncomplete ends a bulk run in which some records failed or were not
+// reached, after the report was printed.
+var errBulkIncomplete = errors.New("some records failed or were not reached")
+
+func runBulk(fs *flag.FlagSet, opts *options, args []string) error {
+	inputPath := fs.String("input", "", "JSONL file of witness inputs of the circuit, one per line, with an optional \"id\" member (required)")
+	outputPath := fs.String("output", "", "JSONL file the results are appended to, one per record (default <input>.proofs.jsonl)")
+	workers := fs.Int("workers", 2, "records proven concurrently; each proof already uses every core, so more workers mostly trade memory for throughput")
+	retryFailed := fs.Bool("retry-failed", false, "prove again the records whose earlier result is an error, instead of skipping them")
+	requireLowS := fs.Bool("require-low-s", false, "reject signatures whose S is above n/2")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *inputPath == "" || *workers < 1 {
+		fmt.Fprintln(fs.Output(), "-input is required and -workers must be positive")
+		fs.Usage()
+		return errUsage
+	}
+	if *outputPath == "" {
+		*outputPath = *inputPath + ".proofs.jsonl"
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	input, err := os.Open(*inputPath)
+	if err != nil {
+		return fmt.Errorf("error opening input: %w", err)
+	}
+	defer input.Close()
+	done, err := readBulkResults(*outputPath, *retryFailed)
+	if err != nil {
+		return err
+	}
+	output, err := os.OpenFile(*outputPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening output: %w", err)
+	}
+	defer output.Close()
+
+	start := time.Now()
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return err
+	}
+	if err := opts.checkBackend(prover.Backend()); err != nil {
+		return err
+	}
+	prover = prover.WithValidationPolicy(zkecdsa.ValidationPolicy{RequireLowS: *requireLowS})
+	r := &bulkReport{
+		Circuit: prover.Circuit(),
+		Curve:   prover.Curve(),
+		Backend: prover.Backend(),
+		Output:  *outputPath,
+		LoadMs:  time.Since(start).Milliseconds(),
+	}
+
+	// A signal stops reading records; the ones in flight are finished and
+	// written so the next run resumes after them. A second signal kills the
+	// process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	records := make(chan bulkRecord)
+	results := make(chan *bulkResult)
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range records {
+				results <- proveRecord(prover, record)
+			}
+		}()
+	}
+	var readErr error
+	go func() {
+		defer close(records)
+		r.Records, r.Skipped, readErr = feedRecords(ctx, input, done, records)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var writeErr error
+	writer := bufio.NewWriter(output)
+	for result := range results {
+		if result.Error == "" {
+			r.Proved++
+		} else {
+			r.Failed++
+		}
+		if writeErr != nil {
+			continue
+		}
+		// One line per result, flushed at once: a crash loses at most the
+		// results in flight
+		line, err := json.Marshal(result)
+		if err != nil {
+			writeErr = err
+			continue
+		}
+		if _, err := writer.Write(append(line, '\n')); err != nil {
+			writeErr = err
+			continue
+		}
+		writeErr = writer.Flush()
+	}
+	if writeErr != nil {
+		return fmt.Errorf("error writing results to %s: %w", *outputPath, writeErr)
+	}
+	if readErr != nil {
+		return fmt.Errorf("error reading records from %s: %w", *inputPath, readErr)
+	}
+	r.Interrupted = ctx.Err() != nil
+	r.ElapsedMs = time.Since(start).Milliseconds()
+	if err := opts.print(r); err != nil {
+		return err
+	}
+	if r.Failed > 0 || r.Interrupted {
+		return errBulkIncomplete
+	}
+	return nil
+}
+
+// feedRecords sends the records of input without a result in done to
+// records, until the end of input or ctx is done, and returns the number of
+// records read and skipped.
+func feedRecords(ctx context.Context, input io.Reader, done map[string]bool, records chan<- bulkRecord) (read, skipped int, err error) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64<<10), maxRecordLen)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		read++
+		key := (&bulkResult{Line: line, ID: recordID(data)}).key()
+		if done[key] {
+			skipped++
+			continue
+		}
+		select {
+		case records <- bulkRecord{line: line, data: append([]byte(nil), data...)}:
+		case <-ctx.Done():
+			return read - 1, skipped, nil
+		}
+	}
+	return read, skipped, scanner.Err()
+}
+
+// recordID returns the normalized "id" member of a record, or nil if it has
+// none or is not a JSON object.
+func recordID(data []byte) json.RawMessage {
+	var record struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(data, &record) != nil {
+		return nil
+	}
+	return normalizeID(record.ID)
+}
+
+// normalizeID re-encodes an id the way json.Marshal writes it to the output,
+// compacted, with sorted members and escaped HTML characters, so that an id
+// matches the one of its earlier result. It returns nil for no id or null.
+func normalizeID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(id))
+	decoder.UseNumber()
+	var value interface{}
+	if decoder.Decode(&value) != nil || value == nil {
+		return nil
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return normalized
+}
+
+// proveRecord proves a record, reporting any failure in the result.
+func proveRecord(prover *zkecdsa.Prover, record bulkRecord) *bulkResult {
+	result := &bulkResult{Line: record.line, ID: recordID(record.data)}
+	input, err := decodeInput(prover.Circuit(), record.data)
+	if err == nil {
+		result.Proof, result.PublicWitness, result.ProveMs, err = proveHex(prover, input)
+	}
+	if err != nil {
+		result.Error, result.ErrorKind = err.Error(), zkecdsa.ErrorKind(err)
+	}
+	return result
+}
+
+// readBulkResults returns the keys of the records with a result in the
+// output of an earlier run, failed ones excepted if retryFailed is set. A
+// last line cut short by a crash is dropped from the file so that appending
+// resumes on a line of its own.
+func readBulkResults(filename string, retryFailed bool) (map[string]bool, error) {
+	done := make(map[string]bool)
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading earlier results: %w", err)
+	}
+	if complete := bytes.LastIndexByte(data, '\n') + 1; complete < len(data) {
+		if err := os.Truncate(filename, int64(complete)); err != nil {
+			return nil, fmt.Errorf("error dropping the partial last result: %w", err)
+		}
+		data = data[:complete]
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var result bulkResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return nil, fmt.Errorf("malformed earlier result at line %d of %s: %w", i+1, filename, err)
+		}
+		if result.Error == "" || !retryFailed {
+			done[result.key()] = true
+		}
+	}
+	return done, nil
+}
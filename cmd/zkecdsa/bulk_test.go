@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string // empty for no id
+	}{
+		{``, ``},
+		{`null`, ``},
+		{`"a"`, `"a"`},
+		{`"a&b"`, `"a\u0026b"`},
+		{`12345678901234567890`, `12345678901234567890`},
+		{`1.50`, `1.50`},
+		{`{ "y": [1, 2], "x": 1 }`, `{"x":1,"y":[1,2]}`},
+		{`{"x"`, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := string(normalizeID(json.RawMessage(tt.id))); got != tt.want {
+				t.Errorf("normalizeID(%s) = %s, want %s", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkResume(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": "a&b", "r": "01"}`,
+		`{"id": {"run": 1, "n": 2}, "r": "02"}`,
+		``,
+		`{"r": "03"}`,
+		`{"id": null, "r": "04"}`,
+		`{"id": 7, "r": "05"}`,
+		`not json`,
+	}, "\n")
+	tests := []struct {
+		name        string
+		results     []bulkResult // Results of an earlier run
+		partial     string       // Line cut short after the results
+		retryFailed bool
+		want        []int // Lines fed to the workers
+		skipped     int
+	}{
+		{
+			name: "no earlier run",
+			want: []int{1, 2, 4, 5, 6, 7},
+		},
+		{
+			name: "ids and lines",
+			results: []bulkResult{
+				{Line: 9, ID: json.RawMessage(`"a&b"`), Proof: "00"},
+				{Line: 2, ID: json.RawMessage(`{"n":2,"run":1}`), Proof: "00"},
+				{Line: 4, Proof: "00"},
+				{Line: 5, Proof: "00"},
+			},
+			want:    []int{6, 7},
+			skipped: 4,
+		},
+		{
+			name: "failed records skipped",
+			results: []bulkResult{
+				{Line: 6, ID: json.RawMessage(`7`), Error: "invalid input", ErrorKind: "invalid_input"},
+				{Line: 7, Error: "malformed record", ErrorKind: "invalid_input"},
+			},
+			want:    []int{1, 2, 4, 5},
+			skipped: 2,
+		},
+		{
+			name: "failed records retried",
+			results: []bulkResult{
+				{Line: 6, ID: json.RawMessage(`7`), Error: "invalid input", ErrorKind: "invalid_input"},
+				{Line: 1, ID: json.RawMessage(`"a&b"`), Proof: "00"},
+			},
+			retryFailed: true,
+			want:        []int{2, 4, 5, 6, 7},
+			skipped:     1,
+		},
+		{
+			name:    "partial last result",
+			results: []bulkResult{{Line: 4, Proof: "00"}},
+			partial: `{"line":5,"proof":"0`,
+			want:    []int{1, 2, 5, 6, 7},
+			skipped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "proofs.jsonl")
+			if tt.results != nil {
+				var output []byte
+				for _, result := range tt.results {
+					line, err := json.Marshal(result)
+					if err != nil {
+						t.Fatal(err)
+					}
+					output = append(append(output, line...), '\n')
+				}
+				complete := len(output)
+				output = append(output, tt.partial...)
+				if err := os.WriteFile(filename, output, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				defer func() {
+					if info, err := os.Stat(filename); err != nil || info.Size() != int64(complete) {
+						t.Errorf("earlier results not truncated to their %d complete bytes", complete)
+					}
+				}()
+			}
+			done, err := readBulkResults(filename, tt.retryFailed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			records := make(chan bulkRecord, 16)
+			read, skipped, err := feedRecords(context.Background(), strings.NewReader(input), done, records)
+			close(records)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lines []int
+			for record := range records {
+				lines = append(lines, record.line)
+			}
+			if read != 6 || skipped != tt.skipped {
+				t.Errorf("feedRecords read %d and skipped %d records, want 6 and %d", read, skipped, tt.skipped)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("feedRecords fed lines %v, want %v", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadBulkResultsMalformed(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "proofs.jsonl")
+	if err := os.WriteFile(filename, []byte("{\"line\":1}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readBulkResults(filename, false); err == nil {
+		t.Error("readBulkResults accepted a malformed result")
+	}
+}
+
+func TestFeedRecordsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	read, skipped, err := feedRecords(ctx, strings.NewReader("{}\n{}\n"), nil, make(chan bulkRecord))
+	if err != nil || read != 0 || skipped != 0 {
+		t.Errorf("feedRecords after cancellation = %d, %d, %v, want 0, 0, nil", read, skipped, err)
+	}
+}
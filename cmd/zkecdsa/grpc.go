@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa/zkecdsapb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer implements the Prover service of zkecdsapb over the job queue
+// and artifacts of the HTTP server, so both share the proof slots.
+type grpcServer struct {
+	zkecdsapb.UnimplementedProverServer
+	*server
+	verifyingKey []byte // Encoded once for GetVerifyingKey
+}
+
+func newGRPCServer(s *server) (*grpcServer, error) {
+	vk, err := zkecdsa.MarshalVerifyingKey(s.vk)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcServer{server: s, verifyingKey: vk}, nil
+}
+
+func (g *grpcServer) Prove(ctx context.Context, req *zkecdsapb.ProveRequest) (*zkecdsapb.ProveResponse, error) {
+	input, err := g.requestInput(req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	j, err := g.jobs.submit(ctx, input, false)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if j, err = g.jobs.await(ctx, j); err != nil {
+		return nil, grpcError(err)
+	}
+	resp, jobErr := proveResponse(req.GetId(), j)
+	if jobErr != nil {
+		return nil, kindStatus(kindCode(jobErr.Kind), jobErr)
+	}
+	return resp, nil
+}
+
+// ProveStream submits the requests as they arrive, waiting for room in the
+// queue so that a large batch is throttled rather than rejected, and sends
+// the responses as the jobs finish.
+func (g *grpcServer) ProveStream(stream zkecdsapb.Prover_ProveStreamServer) error {
+	ctx := stream.Context()
+	responses := make(chan *zkecdsapb.ProveResponse)
+	respond := func(resp *zkecdsapb.ProveResponse) {
+		select {
+		case responses <- resp:
+		case <-ctx.Done():
+		}
+	}
+	received := make(chan error, 1)
+	go func() {
+		var wg sync.WaitGroup
+		defer close(responses)
+		defer wg.Wait()
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				received <- err
+				return
+			}
+			input, err := g.requestInput(req)
+			var j job
+			if err == nil {
+				j, err = g.jobs.submit(ctx, input, true)
+			}
+			if err != nil {
+				respond(&zkecdsapb.ProveResponse{Id: req.GetId(), Error: errorMessage(err)})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				finished, err := g.jobs.await(ctx, j)
+				if err != nil {
+					return
+				}
+				resp, jobErr := proveResponse(req.GetId(), finished)
+				if jobErr != nil {
+					resp = &zkecdsapb.ProveResponse{Id: req.GetId(), Error: jobErr}
+				}
+				respond(resp)
+			}()
+		}
+	}()
+	for resp := range responses {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return <-received
+}
+
+func (g *grpcServer) Verify(ctx context.Context, req *zkecdsapb.VerifyRequest) (*zkecdsapb.VerifyResponse, error) {
+	start := time.Now()
+	var err error
+	switch against := req.GetAgainst().(type) {
+	case *zkecdsapb.VerifyRequest_PublicWitness:
+		err = g.verify(req.GetProof(), against.PublicWitness, nil)
+	case *zkecdsapb.VerifyRequest_Ecdsa:
+		err = g.verify(req.GetProof(), nil, against.Ecdsa.ProveInput())
+	case *zkecdsapb.VerifyRequest_InputJson:
+		var input zkecdsa.Input
+		if input, err = decodeInput(g.manifest.Circuit, []byte(against.InputJson)); err == nil {
+			err = g.verify(req.GetProof(), nil, input)
+		}
+	default:
+		err = fmt.Errorf("%w: one of public_witness, ecdsa and input_json is required", zkecdsa.ErrInvalidInput)
+	}
+	resp := &zkecdsapb.VerifyResponse{Valid: err == nil, VerifyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		if !errors.Is(err, zkecdsa.ErrVerificationFailed) {
+			return nil, grpcError(err)
+		}
+		resp.Error = errorMessage(err)
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) GetVerifyingKey(context.Context, *zkecdsapb.GetVerifyingKeyRequest) (*zkecdsapb.GetVerifyingKeyResponse, error) {
+	return &zkecdsapb.GetVerifyingKeyResponse{
+		VerifyingKey: g.verifyingKey,
+		Circuit:      string(g.manifest.Circuit),
+		Curve:        string(g.manifest.Curve),
+		Backend:      string(g.backend),
+	}, nil
+}
+
+// requestInput returns the input of req for the circuit of the server.
+func (g *grpcServer) requestInput(req *zkecdsapb.ProveRequest) (zkecdsa.Input, error) {
+	switch input := req.GetInput().(type) {
+	case *zkecdsapb.ProveRequest_Ecdsa:
+		return input.Ecdsa.ProveInput(), nil
+	case *zkecdsapb.ProveRequest_InputJson:
+		return decodeInput(g.jobs.prover.Circuit(), []byte(input.InputJson))
+	default:
+		return nil, fmt.Errorf("%w: one of ecdsa and input_json is required", zkecdsa.ErrInvalidInput)
+	}
+}
+
+// proveResponse returns the response of the finished job j, or its error.
+func proveResponse(id string, j job) (*zkecdsapb.ProveResponse, *zkecdsapb.Error) {
+	if j.Status != jobDone {
+		return nil, &zkecdsapb.Error{Message: j.Error, Kind: j.ErrorKind}
+	}
+	proof, err := hex.DecodeString(j.Proof)
+	if err != nil {
+		return nil, errorMessage(err)
+	}
+	publicWitness, err := hex.DecodeString(j.PublicWitness)
+	if err != nil {
+		return nil, errorMessage(err)
+	}
+	return &zkecdsapb.ProveResponse{Id: id, Proof: proof, PublicWitness: publicWitness, ProveMs: j.ProveMs}, nil
+}
+
+func errorMessage(err error) *zkecdsapb.Error {
+	return &zkecdsapb.Error{Message: err.Error(), Kind: serviceErrorKind(err)}
+}
+
+// grpcError returns the status of err, with its kind as detail.
+func grpcError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	code := kindCode(serviceErrorKind(err))
+	if errors.Is(err, errQueueFull) {
+		code = codes.ResourceExhausted
+	}
+	return kindStatus(code, errorMessage(err))
+}
+
+// kindCode maps the error kinds to status codes.
+func kindCode(kind string) codes.Code {
+	switch kind {
+	case "invalid_input", "signature_invalid":
+		return codes.InvalidArgument
+	case "not_found":
+		return codes.NotFound
+	case "unavailable":
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// kindStatus returns the status error of code carrying e as detail, from
+// which zkecdsaclient restores the kind.
+func kindStatus(code codes.Code, e *zkecdsapb.Error) error {
+	s := status.New(code, e.GetMessage())
+	if withDetails, err := s.WithDetails(e); err == nil {
+		s = withDetails
+	}
+	return s.Err()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// inspectReport describes a set of artifacts: the content of its manifest
+// and the state of each file.
+type inspectReport struct {
+	Path string `json:"path"` // Manifest path
+	*zkecdsa.Manifest
+	RunningGnarkVersion string       `json:"runningGnarkVersion,omitempty"`
+	Files               []fileReport `json:"files"`
+	OK                  bool         `json:"ok"` // Every file exists and matches its recorded digest
+}
+
+// fileReport describes an artifact file.
+type fileReport struct {
+	Kind        string              `json:"kind"`
+	Path        string              `json:"path"`
+	Size        int64               `json:"size"`
+	SHA256      string              `json:"sha256,omitempty"`
+	Status      string              `json:"status"`                // "ok", "mismatch", "unrecorded" (no digest in the manifest) or "missing"
+	KeyEncoding zkecdsa.KeyEncoding `json:"keyEncoding,omitempty"` // Proving key only
+}
+
+func (r *inspectReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Manifest:      %s\n", r.Path)
+	fmt.Fprintf(w, "Circuit:       %s\n", r.Circuit)
+	fmt.Fprintf(w, "Curve:         %s\n", r.Curve)
+	fmt.Fprintf(w, "Backend:       %s\n", r.Backend)
+	for _, shape := range []struct {
+		name  string
+		value int
+	}{{"Max message:", r.MaxMessageLen}, {"Batch size:", r.BatchSize}, {"Merkle depth:", r.MerkleDepth}, {"Signers:", r.Signers}, {"Threshold:", r.Threshold}} {
+		if shape.value != 0 {
+			fmt.Fprintf(w, "%-14s %d\n", shape.name, shape.value)
+		}
+	}
+	if r.CommitmentHash != "" {
+		fmt.Fprintf(w, "Hash:          %s\n", r.CommitmentHash)
+	}
+	if r.Constraints != 0 {
+		fmt.Fprintf(w, "Constraints:   %d\n", r.Constraints)
+	}
+	if r.GnarkVersion != "" {
+		fmt.Fprintf(w, "gnark:         %s (running %s)\n", r.GnarkVersion, r.RunningGnarkVersion)
+	}
+	fmt.Fprintln(w, "Files:")
+	for _, f := range r.Files {
+		fmt.Fprintf(w, "  %-18s %-10s %-10s %12d bytes  %s\n", f.Kind, f.Status, f.KeyEncoding, f.Size, f.Path)
+	}
+	if r.OK {
+		fmt.Fprintln(w, "All files present and matching the manifest.")
+	} else {
+		fmt.Fprintln(w, "Some files are missing or do not match the manifest.")
+	}
+}
+
+func runInspect(fs *flag.FlagSet, opts *options, args []string) error {
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	manifest, err := zkecdsa.ReadManifest(paths.Manifest, paths.Circuit, paths.Curve)
+	if err != nil {
+		return err
+	}
+	if err := opts.checkBackend(manifest.Backend); err != nil {
+		return err
+	}
+	r := &inspectReport{Manifest: manifest, Path: paths.Manifest, RunningGnarkVersion: zkecdsa.GnarkVersion(), OK: true}
+	for _, file := range []struct {
+		kind, path, digest string
+	}{
+		{"constraint system", paths.R1CS, manifest.SHA256.R1CS},
+		{"proving key", paths.ProvingKey, manifest.SHA256.ProvingKey},
+		{"verifying key", paths.VerifyingKey, manifest.SHA256.VerifyingKey},
+	} {
+		f, err := inspectFile(file.kind, file.path, file.digest)
+		if err != nil {
+			return err
+		}
+		if f.Status == "missing" || f.Status == "mismatch" {
+			r.OK = false
+		}
+		r.Files = append(r.Files, f)
+	}
+	return opts.print(r)
+}
+
+// inspectFile describes the artifact file at path, whose manifest records
+// digest, possibly empty.
+func inspectFile(kind, path, digest string) (fileReport, error) {
+	f := fileReport{Kind: kind, Path: path}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		f.Status = "missing"
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	f.Size = info.Size()
+	if f.SHA256, err = zkecdsa.FileDigest(path); err != nil {
+		return f, err
+	}
+	switch {
+	case digest == "":
+		f.Status = "unrecorded"
+	case digest == f.SHA256:
+		f.Status = "ok"
+	default:
+		f.Status = "mismatch"
+	}
+	if kind == "proving key" {
+		if f.KeyEncoding, err = zkecdsa.ProvingKeyEncoding(path); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// jobStatus is the state of a proving job.
+type jobStatus string
+
+const (
+	jobQueued  jobStatus = "queued"
+	jobRunning jobStatus = "running"
+	jobDone    jobStatus = "done"
+	jobFailed  jobStatus = "failed"
+)
+
+// job is an input submitted for proving and, once finished, its proof or
+// the error it failed with.
+type job struct {
+	ID            string    `json:"id"`
+	Status        jobStatus `json:"status"`
+	Created       time.Time `json:"created"`
+	Finished      time.Time `json:"finished,omitzero"`
+	Proof         string    `json:"proof,omitempty"`         // Hex, see MarshalProof
+	PublicWitness string    `json:"publicWitness,omitempty"` // Hex, see MarshalPublicWitness
+	ProveMs       int64     `json:"proveMs,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	ErrorKind     string    `json:"errorKind,omitempty"` // See serviceErrorKind
+
+	input zkecdsa.Input   // Dropped once proven
+	ctx   context.Context // Cancels the job while it is queued
+	done  chan struct{}   // Closed once finished
+}
+
+var (
+	errQueueFull    = errors.New("too many proofs queued, retry later")
+	errShuttingDown = errors.New("server shutting down")
+	errJobNotFound  = errors.New("job not found or expired")
+)
+
+// jobQueue proves submitted inputs a fixed number at a time, so that the
+// memory of concurrent proofs stays bounded, with a bounded number waiting
+// for their turn. Finished jobs are kept for a while so their results can
+// be fetched.
+type jobQueue struct {
+	prover   *zkecdsa.Prover
+	ttl      time.Duration
+	admitted chan struct{} // A token per job queued or running
+	running  chan struct{} // A token per job running
+	stopping chan struct{} // Closed by close
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	closed bool
+}
+
+// newJobQueue returns a queue running up to workers proofs with prover and
+// holding up to queueLen more jobs waiting. Finished jobs are kept for ttl.
+func newJobQueue(prover *zkecdsa.Prover, workers, queueLen int, ttl time.Duration) *jobQueue {
+	return &jobQueue{
+		prover:   prover,
+		ttl:      ttl,
+		admitted: make(chan struct{}, workers+queueLen),
+		running:  make(chan struct{}, workers),
+		stopping: make(chan struct{}),
+		jobs:     make(map[string]*job),
+	}
+}
+
+// submit queues input and returns its job. When the queue is full it
+// returns errQueueFull, or waits for room if wait is set. ctx bounds that
+// wait and cancels the job while it is queued.
+func (q *jobQueue) submit(ctx context.Context, input zkecdsa.Input, wait bool) (job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return job{}, fmt.Errorf("error generating job id: %w", err)
+	}
+	if wait {
+		select {
+		case q.admitted <- struct{}{}:
+		case <-ctx.Done():
+			return job{}, ctx.Err()
+		case <-q.stopping:
+			return job{}, errShuttingDown
+		}
+	} else {
+		select {
+		case q.admitted <- struct{}{}:
+		default:
+			return job{}, errQueueFull
+		}
+	}
+	j := &job{ID: hex.EncodeToString(id), Status: jobQueued, Created: time.Now(), input: input, ctx: ctx, done: make(chan struct{})}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		<-q.admitted
+		return job{}, errShuttingDown
+	}
+	q.expire(j.Created)
+	q.jobs[j.ID] = j
+	q.wg.Add(1)
+	go q.run(j)
+	return *j, nil
+}
+
+// get returns the job with the given id.
+func (q *jobQueue) get(id string) (job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return job{}, errJobNotFound
+	}
+	return *j, nil
+}
+
+// await waits for j to finish, or for ctx to be done, and returns it. The
+// job is then forgotten: its result is delivered to the caller alone.
+func (q *jobQueue) await(ctx context.Context, j job) (job, error) {
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return job{}, ctx.Err()
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	finished, ok := q.jobs[j.ID]
+	if !ok {
+		return job{}, errJobNotFound
+	}
+	delete(q.jobs, j.ID)
+	return *finished, nil
+}
+
+// counts returns the number of jobs waiting for a proof slot and of jobs
+// holding one.
+func (q *jobQueue) counts() (queued, running int) {
+	running = len(q.running)
+	return max(len(q.admitted)-running, 0), running
+}
+
+// expire forgets the jobs finished for longer than the ttl. The caller must
+// hold q.mu.
+func (q *jobQueue) expire(now time.Time) {
+	for id, j := range q.jobs {
+		if !j.Finished.IsZero() && now.Sub(j.Finished) > q.ttl {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// run waits for a proof slot and proves j, unless the job is canceled or the
+// queue closed first.
+func (q *jobQueue) run(j *job) {
+	defer q.wg.Done()
+	defer func() { <-q.admitted }()
+	var err error
+	select {
+	case q.running <- struct{}{}:
+		defer func() { <-q.running }()
+	case <-j.ctx.Done():
+		err = j.ctx.Err()
+	case <-q.stopping:
+		err = errShuttingDown
+	}
+	q.mu.Lock()
+	if err == nil && q.closed {
+		err = errShuttingDown
+	}
+	if err == nil {
+		j.Status = jobRunning
+	}
+	q.mu.Unlock()
+	var proof, publicWitness string
+	var proveMs int64
+	if err == nil {
+		proof, publicWitness, proveMs, err = proveHex(q.prover, j.input)
+	}
+	q.mu.Lock()
+	j.Finished, j.ProveMs, j.input, j.ctx = time.Now(), proveMs, nil, nil
+	if err != nil {
+		j.Status, j.Error, j.ErrorKind = jobFailed, err.Error(), serviceErrorKind(err)
+	} else {
+		j.Status, j.Proof, j.PublicWitness = jobDone, proof, publicWitness
+	}
+	q.mu.Unlock()
+	close(j.done)
+}
+
+// close stops accepting jobs and fails the queued ones; the running ones are
+// finished. It returns the number of jobs abandoned.
+func (q *jobQueue) close() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return 0
+	}
+	q.closed = true
+	close(q.stopping)
+	abandoned := 0
+	for _, j := range q.jobs {
+		if j.Status == jobQueued {
+			abandoned++
+		}
+	}
+	return abandoned
+}
+
+// wait waits for the running jobs to finish after close, or for ctx to be
+// done.
+func (q *jobQueue) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
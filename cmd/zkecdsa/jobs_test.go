@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// The queues below have no proof slot, so their jobs stay queued and never
+// reach the prover.
+
+func TestJobQueueSubmit(t *testing.T) {
+	tests := []struct {
+		name     string
+		queueLen int
+		submits  int
+		wait     bool
+		want     error // Error of the last submit
+	}{
+		{"room left", 3, 2, false, nil},
+		{"queue full", 2, 3, false, errQueueFull},
+		{"queue full, waiting", 2, 3, true, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newJobQueue(nil, 0, tt.queueLen, time.Minute)
+			defer q.close()
+			// The deadline bounds the wait of the last submit alone: it
+			// would cancel the jobs queued with it.
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			var err error
+			for i := 0; i < tt.submits; i++ {
+				submitCtx := context.Background()
+				if tt.wait && i == tt.submits-1 {
+					submitCtx = ctx
+				}
+				var j job
+				j, err = q.submit(submitCtx, nil, tt.wait)
+				if err == nil && j.Status != jobQueued {
+					t.Errorf("submitted job is %s, want queued", j.Status)
+				}
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("last submit = %v, want %v", err, tt.want)
+			}
+			if queued, running := q.counts(); queued != min(tt.submits, tt.queueLen) || running != 0 {
+				t.Errorf("counts() = %d, %d, want %d, 0", queued, running, min(tt.submits, tt.queueLen))
+			}
+		})
+	}
+}
+
+func TestJobQueueCancel(t *testing.T) {
+	q := newJobQueue(nil, 0, 1, time.Minute)
+	defer q.close()
+	ctx, cancel := context.WithCancel(context.Background())
+	j, err := q.submit(ctx, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	finished, err := q.await(context.Background(), j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finished.Status != jobFailed || finished.Error != context.Canceled.Error() {
+		t.Errorf("canceled job is %s with error %q, want failed with %q", finished.Status, finished.Error, context.Canceled)
+	}
+	if _, err := q.get(j.ID); !errors.Is(err, errJobNotFound) {
+		t.Errorf("get after await = %v, want errJobNotFound", err)
+	}
+	if queued, _ := q.counts(); queued != 0 {
+		t.Errorf("%d jobs queued after cancellation, want 0", queued)
+	}
+}
+
+func TestJobQueueClose(t *testing.T) {
+	q := newJobQueue(nil, 0, 4, time.Minute)
+	var jobs []job
+	for i := 0; i < 3; i++ {
+		j, err := q.submit(context.Background(), nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, j)
+	}
+	if abandoned := q.close(); abandoned != 3 {
+		t.Errorf("close() abandoned %d jobs, want 3", abandoned)
+	}
+	if abandoned := q.close(); abandoned != 0 {
+		t.Errorf("second close() abandoned %d jobs, want 0", abandoned)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.wait(ctx); err != nil {
+		t.Fatalf("wait() = %v", err)
+	}
+	for _, j := range jobs {
+		finished, err := q.get(j.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if finished.Status != jobFailed || finished.Error != errShuttingDown.Error() {
+			t.Errorf("abandoned job is %s with error %q, want failed with %q", finished.Status, finished.Error, errShuttingDown)
+		}
+	}
+	for _, wait := range []bool{false, true} {
+		if _, err := q.submit(context.Background(), nil, wait); !errors.Is(err, errShuttingDown) {
+			t.Errorf("submit(wait=%v) after close = %v, want errShuttingDown", wait, err)
+		}
+	}
+}
+
+func TestJobQueueExpire(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		finished time.Time
+		kept     bool
+	}{
+		{"queued", time.Time{}, true},
+		{"just finished", now, true},
+		{"finished within the ttl", now.Add(-59 * time.Second), true},
+		{"finished before the ttl", now.Add(-61 * time.Second), false},
+	}
+	q := newJobQueue(nil, 0, 0, time.Minute)
+	for _, tt := range tests {
+		q.jobs[tt.name] = &job{ID: tt.name, Finished: tt.finished}
+	}
+	q.expire(now)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := q.get(tt.name); (err == nil) != tt.kept {
+				t.Errorf("get after expire = %v, want the job kept: %v", err, tt.kept)
+			}
+		})
+	}
+}
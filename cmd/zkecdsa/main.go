@@ -0,0 +1,224 @@
+// Command zkecdsa sets up the circuits of the zkecdsa package, proves and
+// verifies with their artifacts and inspects them, one step per subcommand so
+// each can be scripted:
+//
+//	zkecdsa setup   -curve p256 -backend groth16 -dir artifacts
+//	zkecdsa prove   -dir artifacts -input input.json -proof proof.bin
+//	zkecdsa bulk    -dir artifacts -input inputs.jsonl -output proofs.jsonl -workers 4
+//	zkecdsa serve   -dir artifacts -addr 127.0.0.1:8080 -max-proofs 2
+//	zkecdsa verify  -dir artifacts -proof proof.bin -public-witness public_witness.bin
+//	zkecdsa inspect -dir artifacts -format json
+//
+// Every subcommand prints a report in the -format given, text or json, and
+// exits with status 0 on success, 1 on failure and 2 on a usage error.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// command is a subcommand: run parses args with the flags of the command,
+// registered on fs next to the shared options.
+type command struct {
+	name    string
+	summary string
+	run     func(fs *flag.FlagSet, opts *options, args []string) error
+}
+
+var commands = []command{
+	{"setup", "compile a circuit, run the setup and write its artifacts", runSetup},
+	{"prove", "prove a witness input JSON with the artifacts, writing the proof and public witness", runProve},
+	{"bulk", "prove every witness input of a JSONL file with a worker pool, resuming an earlier run", runBulk},
+	{"serve", "serve proofs and verifications over HTTP, proving queued jobs with the artifacts loaded once", runServe},
+	{"verify", "verify a proof against the verifying key and a public witness or input JSON", runVerify},
+	{"inspect", "print the manifest of the artifacts and check the files against it", runInspect},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet("zkecdsa "+cmd.name, flag.ContinueOnError)
+		var opts options
+		opts.register(fs)
+		err := cmd.run(fs, &opts, os.Args[2:])
+		switch {
+		case err == nil:
+		case errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		case errors.Is(err, errBulkIncomplete):
+			os.Exit(1)
+		default:
+			opts.printError(err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "zkecdsa: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: zkecdsa <command> [flags]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun zkecdsa <command> -h for the flags of a command.")
+}
+
+// errUsage reports invalid flags, already explained by the flag set.
+var errUsage = errors.New("usage error")
+
+// parse parses args into fs, mapping flag errors to errUsage.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// options holds the flags shared by every command: the artifacts to use and
+// the report and log formats.
+type options struct {
+	paths     zkecdsa.Paths
+	circuit   string
+	curve     string
+	backend   string
+	format    string
+	logFormat string
+	verbose   bool
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.circuit, "circuit", string(zkecdsa.CircuitECDSA), "circuit: ecdsa, message, webauthn, batch, membership, nullifier or threshold")
+	fs.StringVar(&o.curve, "curve", string(zkecdsa.P256), "signature curve: p256 or secp256k1")
+	fs.StringVar(&o.backend, "backend", "", "proof system: groth16 or plonk (setup: default groth16; other commands: fail unless the artifacts are for it, default any)")
+	fs.IntVar(&o.paths.BatchSize, "batch-size", zkecdsa.DefaultBatchSize, "batch circuit only: signatures per proof")
+	fs.StringVar(&o.paths.Dir, "dir", "", "artifact directory (default $"+zkecdsa.EnvArtifactDir+" or the working directory)")
+	fs.StringVar(&o.paths.R1CS, "r1cs", "", "constraint system path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.R1CSFile+")")
+	fs.StringVar(&o.paths.ProvingKey, "pk", "", "proving key path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.ProvingKeyFile+")")
+	fs.StringVar(&o.paths.VerifyingKey, "vk", "", "verifying key path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.VerifyingKeyFile+")")
+	fs.StringVar(&o.paths.Manifest, "manifest", "", "manifest path (default <dir>/[<circuit>[-<size>]/]<curve>/"+zkecdsa.ManifestFile+")")
+	fs.StringVar(&o.format, "format", "text", "report format: text or json")
+	fs.StringVar(&o.logFormat, "log-format", "text", "log format: text or json")
+	fs.BoolVar(&o.verbose, "v", false, "log each proof and verification and the progress of gnark to the standard error")
+}
+
+// resolve checks the shared flags and returns the resolved artifact paths.
+func (o *options) resolve() (zkecdsa.Paths, error) {
+	if o.format != "text" && o.format != "json" {
+		return zkecdsa.Paths{}, fmt.Errorf("unsupported format %q (supported: text, json)", o.format)
+	}
+	if o.logFormat != "text" && o.logFormat != "json" {
+		return zkecdsa.Paths{}, fmt.Errorf("unsupported log format %q (supported: text, json)", o.logFormat)
+	}
+	if o.verbose {
+		zkecdsa.SetLogger(o.logger(slog.LevelDebug))
+	} else {
+		zkecdsa.SetLogger(nil)
+	}
+	circuit, err := zkecdsa.ParseCircuitType(o.circuit)
+	if err != nil {
+		return zkecdsa.Paths{}, err
+	}
+	curve, err := zkecdsa.ParseCurve(o.curve)
+	if err != nil {
+		return zkecdsa.Paths{}, err
+	}
+	if o.backend != "" {
+		if _, err := zkecdsa.ParseBackend(o.backend); err != nil {
+			return zkecdsa.Paths{}, err
+		}
+	}
+	paths := o.paths
+	paths.Circuit, paths.Curve = circuit, curve
+	return paths.Resolve(), nil
+}
+
+// logger returns a logger writing to the standard error, the standard output
+// being for the report, in the log format from level up, or from debug level
+// with -v.
+func (o *options) logger(level slog.Level) *slog.Logger {
+	if o.verbose {
+		level = slog.LevelDebug
+	}
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	if o.logFormat == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+}
+
+// checkBackend reports an error if -backend is set and is not backend.
+func (o *options) checkBackend(backend zkecdsa.Backend) error {
+	if o.backend == "" {
+		return nil
+	}
+	if expected, _ := zkecdsa.ParseBackend(o.backend); expected != backend {
+		return fmt.Errorf("artifacts are for %s, not %s", backend, expected)
+	}
+	return nil
+}
+
+// report is the outcome of a command, printed as JSON or as text.
+type report interface {
+	writeText(w io.Writer)
+}
+
+// print writes r to the standard output in the report format.
+func (o *options) print(r report) error {
+	if o.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	r.writeText(os.Stdout)
+	return nil
+}
+
+// errorReport is the JSON report of a failed command.
+type errorReport struct {
+	Error string `json:"error"`
+	Kind  string `json:"kind"` // Class of the error, see zkecdsa.ErrorKind
+}
+
+// printError writes err to the standard error, or as a JSON report to the
+// standard output with -format json.
+func (o *options) printError(err error) {
+	if o.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(errorReport{Error: err.Error(), Kind: zkecdsa.ErrorKind(err)})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
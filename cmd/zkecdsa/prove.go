@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// proveReport describes the proof written by prove.
+type proveReport struct {
+	Circuit       zkecdsa.CircuitType `json:"circuit"`
+	Curve         zkecdsa.Curve       `json:"curve"`
+	Backend       zkecdsa.Backend     `json:"backend"`
+	Proof         string              `json:"proof"`
+	ProofBytes    int                 `json:"proofBytes"`
+	PublicWitness string              `json:"publicWitness"`
+	LoadMs        int64               `json:"loadMs"`
+	ProveMs       int64               `json:"proveMs"`
+}
+
+func (r *proveReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Loaded the %s %s %s artifacts in %dms\n", r.Circuit, r.Curve, r.Backend, r.LoadMs)
+	fmt.Fprintf(w, "Proof generated in %dms\n", r.ProveMs)
+	fmt.Fprintf(w, "Wrote %s (%d bytes)\n", r.Proof, r.ProofBytes)
+	fmt.Fprintf(w, "Wrote %s\n", r.PublicWitness)
+}
+
+func runProve(fs *flag.FlagSet, opts *options, args []string) error {
+	inputPath := fs.String("input", "", "witness input JSON of the circuit, as written by generate_input (required)")
+	proofPath := fs.String("proof", "proof.bin", "proof output path")
+	publicWitnessPath := fs.String("public-witness", "public_witness.bin", "public witness output path")
+	requireLowS := fs.Bool("require-low-s", false, "reject signatures whose S is above n/2")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *inputPath == "" {
+		fmt.Fprintln(fs.Output(), "-input is required")
+		fs.Usage()
+		return errUsage
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	input, err := zkecdsa.ReadCircuitInput(paths.Circuit, *inputPath)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return err
+	}
+	loadTime := time.Since(start)
+	if err := opts.checkBackend(prover.Backend()); err != nil {
+		return err
+	}
+	prover = prover.WithValidationPolicy(zkecdsa.ValidationPolicy{RequireLowS: *requireLowS})
+
+	start = time.Now()
+	proof, publicWitness, err := prover.Prove(input)
+	if err != nil {
+		return err
+	}
+	proveTime := time.Since(start)
+	proofBytes, err := zkecdsa.MarshalProof(proof)
+	if err != nil {
+		return err
+	}
+	publicWitnessBytes, err := zkecdsa.MarshalPublicWitness(publicWitness)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*proofPath, proofBytes, 0o644); err != nil {
+		return fmt.Errorf("error writing proof: %w", err)
+	}
+	if err := os.WriteFile(*publicWitnessPath, publicWitnessBytes, 0o644); err != nil {
+		return fmt.Errorf("error writing public witness: %w", err)
+	}
+	return opts.print(&proveReport{
+		Circuit:       prover.Circuit(),
+		Curve:         prover.Curve(),
+		Backend:       prover.Backend(),
+		Proof:         *proofPath,
+		ProofBytes:    len(proofBytes),
+		PublicWitness: *publicWitnessPath,
+		LoadMs:        loadTime.Milliseconds(),
+		ProveMs:       proveTime.Milliseconds(),
+	})
+}
+
+// decodeInput decodes a witness input JSON of circuit, reporting a malformed
+// one as ErrInvalidInput.
+func decodeInput(circuit zkecdsa.CircuitType, data []byte) (zkecdsa.Input, error) {
+	input, err := zkecdsa.NewCircuitInput(circuit)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, input); err != nil {
+		return nil, fmt.Errorf("%w: malformed input: %v", zkecdsa.ErrInvalidInput, err)
+	}
+	return input, nil
+}
+
+// proveHex proves input and returns the proof and public witness hex
+// encoded, with the proving time. Commands proving many inputs use it so a
+// panic of the solver, as some degenerate inputs trigger, fails the input
+// instead of the process.
+func proveHex(prover *zkecdsa.Prover, input zkecdsa.Input) (proof, publicWitness string, proveMs int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: panic: %v", zkecdsa.ErrProvingFailed, r)
+		}
+	}()
+	start := time.Now()
+	p, w, err := prover.Prove(input)
+	proveMs = time.Since(start).Milliseconds()
+	if err != nil {
+		return "", "", proveMs, err
+	}
+	proofBytes, err := zkecdsa.MarshalProof(p)
+	if err != nil {
+		return "", "", proveMs, err
+	}
+	publicWitnessBytes, err := zkecdsa.MarshalPublicWitness(w)
+	if err != nil {
+		return "", "", proveMs, err
+	}
+	return hex.EncodeToString(proofBytes), hex.EncodeToString(publicWitnessBytes), proveMs, nil
+}
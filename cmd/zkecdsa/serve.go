@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa/zkecdsapb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+)
+
+// server serves proofs and verifications over HTTP with artifacts loaded
+// once:
+//
+//	POST /prove     witness input JSON -> 202 and the queued job
+//	GET  /jobs/{id} -> the job, with its proof once done
+//	POST /verify    verifyRequest -> verifyResponse
+//	GET  /metrics   the Prometheus metrics, see registerMetrics
+//
+// and over gRPC, see grpcServer.
+type server struct {
+	jobs     *jobQueue
+	vk       zkecdsa.VerifyingKey
+	backend  zkecdsa.Backend
+	manifest *zkecdsa.Manifest
+	metrics  http.Handler // GET /metrics
+}
+
+// verifyRequest is the body of POST /verify: a proof and either the public
+// witness or the witness input JSON to verify it against.
+type verifyRequest struct {
+	Proof         string          `json:"proof"`                   // Hex, see MarshalProof
+	PublicWitness string          `json:"publicWitness,omitempty"` // Hex, see MarshalPublicWitness
+	Input         json.RawMessage `json:"input,omitempty"`         // Only its public values are read
+}
+
+// verifyResponse is the result of POST /verify. A proof that does not
+// verify is a valid request: it is answered 200 with Valid false.
+type verifyResponse struct {
+	Valid     bool   `json:"valid"`
+	VerifyMs  int64  `json:"verifyMs"`
+	Error     string `json:"error,omitempty"`
+	ErrorKind string `json:"errorKind,omitempty"` // See zkecdsa.ErrorKind
+}
+
+func runServe(fs *flag.FlagSet, opts *options, args []string) error {
+	addr := fs.String("addr", "127.0.0.1:8080", "HTTP address to listen on, none if empty; the service has no authentication, keep it on localhost")
+	grpcAddr := fs.String("grpc-addr", "", "gRPC address to listen on, none if empty, e.g. 127.0.0.1:9090")
+	metricsAddr := fs.String("metrics-addr", "", "address serving only GET /metrics, none if empty; the HTTP address serves it too")
+	maxProofs := fs.Int("max-proofs", 1, "proofs computed concurrently; each holds a witness and the working memory of the prover, so size it to the memory available")
+	queueLen := fs.Int("queue", 64, "jobs waiting for a proof slot, beyond which POST /prove answers 503")
+	jobTTL := fs.Duration("job-ttl", time.Hour, "how long the result of a finished job can be fetched")
+	shutdownTimeout := fs.Duration("shutdown-timeout", time.Minute, "on SIGINT or SIGTERM, how long to wait for in-flight requests and running proofs")
+	requireLowS := fs.Bool("require-low-s", false, "reject signatures whose S is above n/2")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *maxProofs < 1 || *queueLen < 0 || (*addr == "" && *grpcAddr == "") {
+		fmt.Fprintln(fs.Output(), "-max-proofs must be positive, -queue not negative and -addr or -grpc-addr set")
+		fs.Usage()
+		return errUsage
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	log := opts.logger(slog.LevelInfo)
+	zkecdsa.SetLogger(log)
+	registry := prometheus.NewRegistry()
+	if err := zkecdsa.EnableMetrics(registry); err != nil {
+		return err
+	}
+
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return err
+	}
+	if err := opts.checkBackend(prover.Backend()); err != nil {
+		return err
+	}
+	prover = prover.WithValidationPolicy(zkecdsa.ValidationPolicy{RequireLowS: *requireLowS})
+	vk, err := zkecdsa.LoadVerifyingKey(paths)
+	if err != nil {
+		return err
+	}
+	manifest, err := zkecdsa.ReadManifest(paths.Manifest, paths.Circuit, paths.Curve)
+	if err != nil {
+		return err
+	}
+
+	s := &server{
+		jobs:     newJobQueue(prover, *maxProofs, *queueLen, *jobTTL),
+		vk:       vk,
+		backend:  prover.Backend(),
+		manifest: manifest,
+	}
+	if err := s.registerMetrics(registry); err != nil {
+		return err
+	}
+	s.metrics = promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(log.Handler(), slog.LevelError)})
+	served := make(chan error, 3)
+	var httpServers []*http.Server
+	listenHTTP := func(addr string, handler http.Handler, name string) error {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		httpServers = append(httpServers, httpServer)
+		go func() {
+			served <- httpServer.Serve(listener)
+		}()
+		log.Info("serving "+name, "url", "http://"+listener.Addr().String())
+		return nil
+	}
+	if *addr != "" {
+		if err := listenHTTP(*addr, s.handler(), "HTTP"); err != nil {
+			return err
+		}
+	}
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", s.metrics)
+		if err := listenHTTP(*metricsAddr, mux, "metrics"); err != nil {
+			return err
+		}
+	}
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		service, err := newGRPCServer(s)
+		if err != nil {
+			return err
+		}
+		grpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(maxRecordLen))
+		zkecdsapb.RegisterProverServer(grpcServer, service)
+		go func() {
+			served <- grpcServer.Serve(listener)
+		}()
+		log.Info("serving gRPC", "addr", listener.Addr().String())
+	}
+	log.Info("proving", "circuit", prover.Circuit(), "curve", prover.Curve(), "backend", prover.Backend(), "max_proofs", *maxProofs, "queue", *queueLen)
+
+	// A signal stops accepting connections and abandons the queued jobs,
+	// whose results could not be fetched anyway, then lets in-flight requests
+	// and running proofs finish. A second signal kills the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-served:
+		s.jobs.close()
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	abandoned := s.jobs.close()
+	log.Info("shutting down", "abandoned_jobs", abandoned)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		defer func() {
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				grpcServer.Stop()
+			}
+		}()
+	}
+	for _, httpServer := range httpServers {
+		if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	if waitErr := s.jobs.wait(shutdownCtx); waitErr != nil && err == nil {
+		err = fmt.Errorf("running proofs not finished: %w", waitErr)
+	}
+	return err
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /prove", s.handleProve)
+	mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	mux.HandleFunc("POST /verify", s.handleVerify)
+	mux.Handle("GET /metrics", s.metrics)
+	return mux
+}
+
+// registerMetrics registers with reg the jobs queued and running and the
+// collectors of the Go runtime and of the process, next to the metrics of
+// zkecdsa.EnableMetrics.
+func (s *server) registerMetrics(reg *prometheus.Registry) error {
+	queued := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "zkecdsa_serve_jobs_queued",
+		Help: "Jobs waiting for a proof slot.",
+	}, func() float64 {
+		queued, _ := s.jobs.counts()
+		return float64(queued)
+	})
+	running := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "zkecdsa_serve_jobs_running",
+		Help: "Jobs holding a proof slot.",
+	}, func() float64 {
+		_, running := s.jobs.counts()
+		return float64(running)
+	})
+	for _, c := range []prometheus.Collector{queued, running, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) handleProve(w http.ResponseWriter, r *http.Request) {
+	data, err := readBody(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	input, err := decodeInput(s.jobs.prover.Circuit(), data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	j, err := s.jobs.submit(context.Background(), input, false)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/jobs/"+j.ID)
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+func (s *server) handleJob(w http.ResponseWriter, r *http.Request) {
+	j, err := s.jobs.get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+func (s *server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	data, err := readBody(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var req verifyRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		writeError(w, fmt.Errorf("%w: malformed request: %v", zkecdsa.ErrInvalidInput, err))
+		return
+	}
+	start := time.Now()
+	err = s.verifyHTTP(&req)
+	resp := verifyResponse{Valid: err == nil, VerifyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		if !errors.Is(err, zkecdsa.ErrVerificationFailed) {
+			writeError(w, err)
+			return
+		}
+		resp.Error, resp.ErrorKind = err.Error(), zkecdsa.ErrorKind(err)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// verifyHTTP decodes req and verifies it.
+func (s *server) verifyHTTP(req *verifyRequest) error {
+	if req.Proof == "" || (req.PublicWitness == "") == (len(req.Input) == 0) {
+		return fmt.Errorf("%w: proof and exactly one of publicWitness and input are required", zkecdsa.ErrInvalidInput)
+	}
+	proof, err := hex.DecodeString(req.Proof)
+	if err != nil {
+		return fmt.Errorf("%w: %w: proof: %v", zkecdsa.ErrInvalidInput, zkecdsa.ErrInvalidHex, err)
+	}
+	if req.PublicWitness != "" {
+		publicWitness, err := hex.DecodeString(req.PublicWitness)
+		if err != nil {
+			return fmt.Errorf("%w: %w: publicWitness: %v", zkecdsa.ErrInvalidInput, zkecdsa.ErrInvalidHex, err)
+		}
+		return s.verify(proof, publicWitness, nil)
+	}
+	input, err := decodeInput(s.manifest.Circuit, req.Input)
+	if err != nil {
+		return err
+	}
+	return s.verify(proof, nil, input)
+}
+
+// verify verifies proof against publicWitness or, if it is nil, against the
+// public values of input, shaped as the artifacts are.
+func (s *server) verify(proofBytes, publicWitnessBytes []byte, input zkecdsa.Input) error {
+	proof, err := zkecdsa.UnmarshalProof(s.backend, proofBytes)
+	if err != nil {
+		return err
+	}
+	if publicWitnessBytes != nil {
+		publicWitness, err := zkecdsa.UnmarshalPublicWitness(publicWitnessBytes)
+		if err != nil {
+			return err
+		}
+		return zkecdsa.Verify(proof, s.vk, publicWitness)
+	}
+	if input, err = s.manifest.ShapeInput(input); err != nil {
+		return err
+	}
+	return zkecdsa.VerifyWithPublicInputs(proof, s.vk, input)
+}
+
+// readBody reads the body of r, up to maxRecordLen bytes.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRecordLen))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, fmt.Errorf("%w: body larger than %d bytes", zkecdsa.ErrInvalidInput, tooLarge.Limit)
+	}
+	return data, err
+}
+
+// serviceErrorKind classifies err as zkecdsa.ErrorKind does, adding the kinds of the
+// service: not_found and unavailable.
+func serviceErrorKind(err error) string {
+	switch {
+	case errors.Is(err, errJobNotFound):
+		return "not_found"
+	case errors.Is(err, errQueueFull), errors.Is(err, errShuttingDown):
+		return "unavailable"
+	default:
+		return zkecdsa.ErrorKind(err)
+	}
+}
+
+// writeError answers err as an errorReport, with the status of its kind.
+func writeError(w http.ResponseWriter, err error) {
+	kind := serviceErrorKind(err)
+	status := http.StatusInternalServerError
+	switch kind {
+	case "not_found":
+		status = http.StatusNotFound
+	case "unavailable":
+		status = http.StatusServiceUnavailable
+		w.Header().Set("Retry-After", "10")
+	case "invalid_input", "signature_invalid":
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, errorReport{Error: err.Error(), Kind: kind})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
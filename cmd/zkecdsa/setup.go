@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// setupReport describes the artifacts written by setup.
+type setupReport struct {
+	Circuit      zkecdsa.CircuitType `json:"circuit"`
+	Curve        zkecdsa.Curve       `json:"curve"`
+	Backend      zkecdsa.Backend     `json:"backend"`
+	PublicInputs bool                `json:"publicInputs"`
+	Constraints  int                 `json:"constraints"`
+	KeyEncoding  zkecdsa.KeyEncoding `json:"keyEncoding"`
+	R1CS         string              `json:"r1cs"`
+	ProvingKey   string              `json:"provingKey"`
+	VerifyingKey string              `json:"verifyingKey"`
+	Manifest     string              `json:"manifest"`
+	Solidity     string              `json:"solidity,omitempty"`
+	SetupMs      int64               `json:"setupMs"`
+}
+
+func (r *setupReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Set up the %s %s circuit for %s (public inputs: %t, %d constraints) in %dms\n", r.Circuit, r.Curve, r.Backend, r.PublicInputs, r.Constraints, r.SetupMs)
+	fmt.Fprintf(w, "Wrote %s\n", r.R1CS)
+	fmt.Fprintf(w, "Wrote %s (%s)\n", r.ProvingKey, r.KeyEncoding)
+	fmt.Fprintf(w, "Wrote %s\n", r.VerifyingKey)
+	fmt.Fprintf(w, "Wrote %s\n", r.Manifest)
+	if r.Solidity != "" {
+		fmt.Fprintf(w, "Wrote %s\n", r.Solidity)
+	}
+}
+
+func runSetup(fs *flag.FlagSet, opts *options, args []string) error {
+	var cfg zkecdsa.Config
+	fs.StringVar(&cfg.SRS, "srs", "", "PLONK only: KZG SRS file in canonical form (default: generate an unsafe test SRS)")
+	fs.BoolVar(&cfg.PublicInputs, "public", false, "ECDSA and message only: make the message (hash) and public key public inputs")
+	fs.IntVar(&cfg.MaxMessageLen, "max-message-len", zkecdsa.DefaultMaxMessageLen, "message circuit only: message capacity in bytes")
+	commit := fs.Bool("commit", false, "message and threshold circuits: make a commitment to the message or keys public instead of the message or keys")
+	fs.IntVar(&cfg.MerkleDepth, "merkle-depth", zkecdsa.DefaultMerkleDepth, "membership circuit only: depth of the key tree")
+	fs.IntVar(&cfg.Signers, "signers", zkecdsa.DefaultThresholdSigners, "threshold circuit only: number of keys n")
+	fs.IntVar(&cfg.Threshold, "threshold", zkecdsa.DefaultThreshold, "threshold circuit only: signatures required k")
+	fs.BoolVar(&cfg.PublicThreshold, "public-threshold", false, "threshold circuit only: make k a public input instead of compiling it in")
+	commitmentHash := fs.String("commitment-hash", string(zkecdsa.MiMC), "with -commit or the batch, membership and nullifier circuits: commitment, key tree or nullifier hash, mimc or poseidon2")
+	keyEncodingName := fs.String("key-encoding", string(zkecdsa.KeyCompressed), "proving key encoding: compressed (checked on load) or raw (about twice the size, loads without checks)")
+	solidity := fs.Bool("solidity", false, "circuits with public inputs: also write the Solidity verifier next to the keys")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Backend, err = zkecdsa.ParseBackend(opts.backend); err != nil {
+		return err
+	}
+	if cfg.CommitmentHash, err = zkecdsa.ParseFieldHash(*commitmentHash); err != nil {
+		return err
+	}
+	keyEncoding, err := zkecdsa.ParseKeyEncoding(*keyEncodingName)
+	if err != nil {
+		return err
+	}
+	cfg.Circuit, cfg.Curve, cfg.BatchSize = paths.Circuit, paths.Curve, paths.BatchSize
+	cfg.CommitMessage, cfg.CommitKeys = *commit, *commit
+	if cfg.Backend == zkecdsa.Plonk && cfg.SRS == "" {
+		fmt.Fprintln(os.Stderr, "WARNING: no -srs given, using an unsafe KZG SRS fit for testing only")
+	}
+
+	start := time.Now()
+	artifacts, err := zkecdsa.Setup(cfg)
+	if err != nil {
+		return err
+	}
+	setupTime := time.Since(start)
+	artifacts.KeyEncoding = keyEncoding
+	if err := artifacts.Save(paths); err != nil {
+		return err
+	}
+	r := &setupReport{
+		Circuit:      artifacts.Circuit,
+		Curve:        artifacts.Curve,
+		Backend:      artifacts.Backend,
+		PublicInputs: artifacts.PublicInputs(),
+		Constraints:  artifacts.CCS.GetNbConstraints(),
+		KeyEncoding:  keyEncoding,
+		R1CS:         paths.R1CS,
+		ProvingKey:   paths.ProvingKey,
+		VerifyingKey: paths.VerifyingKey,
+		Manifest:     paths.Manifest,
+		SetupMs:      setupTime.Milliseconds(),
+	}
+	if *solidity {
+		if !artifacts.PublicInputs() {
+			return fmt.Errorf("the private %s circuit has no public input to check on-chain, compile it with -public", artifacts.Circuit)
+		}
+		r.Solidity = filepath.Join(paths.Namespace(), zkecdsa.SolidityVerifierFile)
+		if err := zkecdsa.WriteSolidityVerifier(r.Solidity, artifacts.VK); err != nil {
+			return err
+		}
+	}
+	return opts.print(r)
+}
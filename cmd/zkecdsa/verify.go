@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+)
+
+// verifyReport describes a proof that verified; a failure is reported as an
+// error.
+type verifyReport struct {
+	Valid    bool                `json:"valid"`
+	Circuit  zkecdsa.CircuitType `json:"circuit"`
+	Curve    zkecdsa.Curve       `json:"curve"`
+	Backend  zkecdsa.Backend     `json:"backend"`
+	Against  string              `json:"against"` // "publicWitness" or "input"
+	VerifyMs int64               `json:"verifyMs"`
+}
+
+func (r *verifyReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Proof VALID for the %s %s %s verifying key, against the %s (%dms)\n", r.Circuit, r.Curve, r.Backend, map[string]string{"publicWitness": "public witness", "input": "input public values"}[r.Against], r.VerifyMs)
+}
+
+func runVerify(fs *flag.FlagSet, opts *options, args []string) error {
+	proofPath := fs.String("proof", "", "proof written by prove (required)")
+	publicWitnessPath := fs.String("public-witness", "", "public witness written by prove")
+	inputPath := fs.String("input", "", "witness input JSON of which only the public values are read, for circuits with public inputs")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *proofPath == "" || (*publicWitnessPath == "") == (*inputPath == "") {
+		fmt.Fprintln(fs.Output(), "-proof and exactly one of -public-witness and -input are required")
+		fs.Usage()
+		return errUsage
+	}
+	paths, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	vk, err := zkecdsa.LoadVerifyingKey(paths)
+	if err != nil {
+		return err
+	}
+	backend, err := zkecdsa.BackendOf(vk)
+	if err != nil {
+		return err
+	}
+	if err := opts.checkBackend(backend); err != nil {
+		return err
+	}
+	proofBytes, err := os.ReadFile(*proofPath)
+	if err != nil {
+		return fmt.Errorf("error reading proof: %w", err)
+	}
+	proof, err := zkecdsa.UnmarshalProof(backend, proofBytes)
+	if err != nil {
+		return err
+	}
+
+	r := &verifyReport{Valid: true, Circuit: paths.Circuit, Curve: paths.Curve, Backend: backend}
+	start := time.Now()
+	if *inputPath != "" {
+		r.Against = "input"
+		err = verifyInput(paths, proof, vk, *inputPath)
+	} else {
+		r.Against = "publicWitness"
+		err = verifyPublicWitness(proof, vk, *publicWitnessPath)
+	}
+	if err != nil {
+		return err
+	}
+	r.VerifyMs = time.Since(start).Milliseconds()
+	return opts.print(r)
+}
+
+// verifyInput verifies proof against the public values of the input at
+// inputPath, with the circuit shape recorded in the manifest.
+func verifyInput(paths zkecdsa.Paths, proof zkecdsa.Proof, vk zkecdsa.VerifyingKey, inputPath string) error {
+	manifest, err := zkecdsa.ReadManifest(paths.Manifest, paths.Circuit, paths.Curve)
+	if err != nil {
+		return err
+	}
+	input, err := zkecdsa.ReadCircuitInput(paths.Circuit, inputPath)
+	if err != nil {
+		return err
+	}
+	if input, err = manifest.ShapeInput(input); err != nil {
+		return err
+	}
+	return zkecdsa.VerifyWithPublicInputs(proof, vk, input)
+}
+
+// verifyPublicWitness verifies proof against the public witness at
+// publicWitnessPath.
+func verifyPublicWitness(proof zkecdsa.Proof, vk zkecdsa.VerifyingKey, publicWitnessPath string) error {
+	b, err := os.ReadFile(publicWitnessPath)
+	if err != nil {
+		return fmt.Errorf("error reading public witness: %w", err)
+	}
+	publicWitness, err := zkecdsa.UnmarshalPublicWitness(b)
+	if err != nil {
+		return err
+	}
+	return zkecdsa.Verify(proof, vk, publicWitness)
+}
@@ -1,12 +1,35 @@
 package main
 
 /*
+#include <stdint.h>
 #include <stdlib.h>
 #include <string.h>
 
+typedef enum {
+    ECDSA_OK = 0,
+    ECDSA_ERR_INTERNAL = 1,
+    ECDSA_ERR_INVALID_INPUT = 2,
+    ECDSA_ERR_INVALID_HEX = 3,
+    ECDSA_ERR_INVALID_LENGTH = 4,
+    ECDSA_ERR_INVALID_POINT = 5,
+    ECDSA_ERR_INVALID_SCALAR = 6,
+    ECDSA_ERR_HIGH_S = 7,
+    ECDSA_ERR_ARTIFACT_MISSING = 8,
+    ECDSA_ERR_ARTIFACT_CORRUPT = 9,
+    ECDSA_ERR_SIGNATURE_INVALID = 10,
+    ECDSA_ERR_PROVING_FAILED = 11,
+    ECDSA_ERR_VERIFICATION_FAILED = 12,
+    ECDSA_ERR_PANIC = 13,
+} EcdsaErrorCode;
+
 typedef struct {
     char* error_msg;
     int success;
+    unsigned char* proof;
+    size_t proof_len;
+    unsigned char* public_witness;
+    size_t public_witness_len;
+    EcdsaErrorCode error_code;
 } ProofResult;
 
 typedef struct {
@@ -15,99 +38,127 @@ typedef struct {
     char* s;
     char* pubX;
     char* pubY;
+    char* curve;
+    char* signature;
+    char* publicKey;
 } ProveInput;
+
+typedef struct {
+    char* dir;
+    char* curve;
+    char* r1cs;
+    char* proving_key;
+    char* verifying_key;
+    char* witness_input;
+    char* manifest;
+    char* circuit;
+    size_t batch_size;
+} ArtifactPaths;
+
+typedef struct {
+    unsigned char* authenticator_data;
+    size_t authenticator_data_len;
+    unsigned char* client_data_json;
+    size_t client_data_json_len;
+    unsigned char* signature;
+    size_t signature_len;
+    unsigned char* challenge;
+    size_t challenge_len;
+    char* pubX;
+    char* pubY;
+    char* curve;
+} WebAuthnAssertion;
+
+typedef struct {
+    ProveInput input;
+    char* root;
+    char** path;
+    size_t path_len;
+    uint64_t index;
+} MembershipProveInput;
+
+typedef enum {
+    ECDSA_LOG_DEBUG = -4,
+    ECDSA_LOG_INFO = 0,
+    ECDSA_LOG_WARN = 4,
+    ECDSA_LOG_ERROR = 8,
+} EcdsaLogLevel;
+
+typedef void (*EcdsaLogCallback)(EcdsaLogLevel level, const char* message, const char* attrs_json, void* user_data);
+
+static inline void callLogCallback(EcdsaLogCallback callback, EcdsaLogLevel level, const char* message, const char* attrs_json, void* user_data) {
+    callback(level, message, attrs_json, user_data);
+}
 */
 import "C"
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"io"
-	"math/big"
+	"log/slog"
 	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
-	"github.com/consensys/gnark-crypto/ecc"
-	"github.com/consensys/gnark/backend/groth16"
-	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
-	"github.com/consensys/gnark/std/math/emulated"
-	gnarkecdsa "github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/common/expfmt"
 )
 
-// EcdsaCircuit defines the circuit structure
-type EcdsaCircuit[T, S emulated.FieldParams] struct {
-	Sig gnarkecdsa.Signature[S]
-	Msg emulated.Element[S]
-	Pub gnarkecdsa.PublicKey[T, S]
-}
+// ProveInputEcdsa is the JSON witness input shared with the generator
+type ProveInputEcdsa = zkecdsa.ProveInputEcdsa
 
-func (c *EcdsaCircuit[T, S]) Define(api frontend.API) error {
-	curveParams := sw_emulated.GetCurveParams[T]()
-	c.Pub.Verify(api, curveParams, &c.Msg, &c.Sig)
-	return nil
-}
+// Artifact locations used by the exports that do not take paths, set through
+// SetArtifactPaths. Unset fields fall back to the environment, see zkecdsa.Paths.
+var (
+	artifactPathsMu sync.RWMutex
+	artifactPaths   zkecdsa.Paths
+)
 
-// ProveInputEcdsa struct for JSON serialization
-type ProveInputEcdsa struct {
-	MsgHash string `json:"msgHash"`
-	R       string `json:"r"`
-	S       string `json:"s"`
-	PubX    string `json:"pubX"`
-	PubY    string `json:"pubY"`
+// Helper function returning the resolved process-wide artifact paths for
+// circuit and curve, or for the configured curve if curve is empty
+func artifactPathsFor(circuit zkecdsa.CircuitType, curve zkecdsa.Curve) (zkecdsa.Paths, error) {
+	return configuredPathsFor(circuit, curve, 0)
 }
 
-// Helper function to generate a valid ECDSA signature and key pair
-func generateValidECDSAData() (*ProveInputEcdsa, error) {
-	// Generate a new private key on P-256 curve
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate private key: %w", err)
-	}
-
-	// Generate a random message
-	message := make([]byte, 32)
-	_, err = rand.Read(message)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate random message: %w", err)
-	}
+// Helper function returning the resolved process-wide artifact paths for
+// batches of size signatures on curve
+func batchArtifactPathsFor(size int, curve zkecdsa.Curve) (zkecdsa.Paths, error) {
+	return configuredPathsFor(zkecdsa.CircuitBatch, curve, size)
+}
 
-	// Hash the message
-	hash := sha256.Sum256(message)
+// Helper function behind artifactPathsFor: an empty curve or a zero batch
+// size keeps the configured one
+func configuredPathsFor(circuit zkecdsa.CircuitType, curve zkecdsa.Curve, batchSize int) (zkecdsa.Paths, error) {
+	artifactPathsMu.RLock()
+	paths := artifactPaths
+	artifactPathsMu.RUnlock()
 
-	// Sign the hash
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign message: %w", err)
+	paths.Circuit = circuit
+	if curve != "" {
+		paths.Curve = curve
 	}
-
-	// Create ProveInputEcdsa with proper values
-	proveInput := &ProveInputEcdsa{
-		MsgHash: hex.EncodeToString(hash[:]),
-		R:       hex.EncodeToString(r.Bytes()),
-		S:       hex.EncodeToString(s.Bytes()),
-		PubX:    hex.EncodeToString(privateKey.PublicKey.X.Bytes()),
-		PubY:    hex.EncodeToString(privateKey.PublicKey.Y.Bytes()),
+	if batchSize != 0 {
+		paths.BatchSize = batchSize
 	}
-
-	// Verify the signature is correct (sanity check)
-	if !ecdsa.Verify(&privateKey.PublicKey, hash[:], r, s) {
-		return nil, fmt.Errorf("generated signature verification failed")
+	if _, err := zkecdsa.ParseCurve(string(paths.Curve)); err != nil {
+		return zkecdsa.Paths{}, err
 	}
-
-	return proveInput, nil
+	return paths.Resolve(), nil
 }
 
 // Helper function to create a variant of the original input with valid ECDSA data
 func createVariantProveInput(original *ProveInputEcdsa) *ProveInputEcdsa {
 	// Generate a completely new valid ECDSA signature and key pair
-	variant, err := generateValidECDSAData()
+	variant, err := zkecdsa.GenerateRandomInput(original.Curve)
 	if err != nil {
 		fmt.Printf("Warning: Failed to generate valid ECDSA data, using original: %v\n", err)
 		// If generation fails, add timestamp to original to make it different
@@ -118,11 +169,14 @@ func createVariantProveInput(original *ProveInputEcdsa) *ProveInputEcdsa {
 			S:       original.S,
 			PubX:    original.PubX,
 			PubY:    original.PubY,
+			Curve:   original.Curve,
 		}
 	}
-	
+
 	return variant
 }
+
+// Helper function to convert C string to Go string
 func cStringToGoString(cStr *C.char) string {
 	if cStr == nil {
 		return ""
@@ -142,69 +196,33 @@ func freeCString(cStr *C.char) {
 	}
 }
 
-// readFromFile helper function
-func readFromFile(filename string, data interface{}) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return fmt.Errorf("error opening file %s: %w", filename, err)
-	}
-	defer file.Close()
-
-	switch v := data.(type) {
-	case io.ReaderFrom:
-		_, err = v.ReadFrom(file)
-		if err != nil && err != io.EOF {
-			return fmt.Errorf("error reading from file %s into io.ReaderFrom: %w", filename, err)
-		}
-	case *ProveInputEcdsa:
-		decoder := json.NewDecoder(file)
-		err = decoder.Decode(v)
-		if err != nil {
-			return fmt.Errorf("error decoding JSON from file %s: %w", filename, err)
-		}
-	default:
-		return fmt.Errorf("unsupported type for reading from file: %T", data)
-	}
-
-	return nil
-}
-
 // Core proof generation and verification logic
 func performProofVerification() error {
 	fmt.Println("\n--- Testing ReadFromFile and re-verification ---")
 
-	// 1. Read back the compiled circuit
-	loadedR1CS := groth16.NewCS(ecc.BN254)
-	err := readFromFile("r1cs.bin", loadedR1CS)
+	// 1. Read back the compiled circuit and keys
+	paths, err := artifactPathsFor(zkecdsa.CircuitECDSA, "")
 	if err != nil {
-		return fmt.Errorf("error reading r1cs.bin: %w", err)
+		return err
 	}
-	fmt.Printf("Read r1cs.bin (Constraints: %d)\n", loadedR1CS.GetNbConstraints())
-
-	// 2. Read back the proving key
-	loadedPK := groth16.NewProvingKey(ecc.BN254)
-	err = readFromFile("proving_key.bin", loadedPK)
+	artifacts, err := zkecdsa.LoadArtifacts(paths)
 	if err != nil {
-		return fmt.Errorf("error reading proving_key.bin: %w", err)
+		return err
 	}
-	fmt.Println("Read proving_key.bin")
+	fmt.Printf("Read %s (Constraints: %d)\n", paths.R1CS, artifacts.CCS.GetNbConstraints())
+	fmt.Printf("Read %s (%s)\n", paths.ProvingKey, artifacts.KeyEncoding)
+	fmt.Printf("Read %s\n", paths.VerifyingKey)
 
-	// 3. Read back the verifying key
-	loadedVK := groth16.NewVerifyingKey(ecc.BN254)
-	err = readFromFile("verifying_key.bin", loadedVK)
+	// 2. Read back the prove input JSON
+	loadedProveInput, err := zkecdsa.ReadInput(paths.WitnessInput)
 	if err != nil {
-		return fmt.Errorf("error reading verifying_key.bin: %w", err)
+		return err
 	}
-	fmt.Println("Read verifying_key.bin")
-
-	// 4. Read back the prove input JSON
-	var loadedProveInput ProveInputEcdsa
-	err = readFromFile("witness_input.json", &loadedProveInput)
-	if err != nil {
-		return fmt.Errorf("error reading witness_input.json: %w", err)
+	fmt.Printf("Read %s\n", paths.WitnessInput)
+	if loadedProveInput.Curve == "" {
+		loadedProveInput.Curve = paths.Curve
 	}
-	fmt.Println("Read witness_input.json")
-	
+
 	// Display the ProveInput data for reference
 	fmt.Println("\n--- ProveInput Data (for C interface reference) ---")
 	fmt.Printf("MsgHash: %s\n", loadedProveInput.MsgHash)
@@ -214,175 +232,434 @@ func performProofVerification() error {
 	fmt.Printf("PubY:    %s\n", loadedProveInput.PubY)
 	fmt.Println("--- End ProveInput Data ---")
 
-	// Decode hex strings back to big.Int and byte slices
-	rBytes, err := hex.DecodeString(loadedProveInput.R)
+	// 3. Perform proof and verification
+	fmt.Println("\n--- Proving and Verifying with loaded artifacts ---")
+
+	// Prove
+	proofLoaded, publicWitnessLoaded, err := artifacts.Prover().Prove(loadedProveInput)
 	if err != nil {
-		return fmt.Errorf("error decoding R hex: %w", err)
+		return err
 	}
-	sBytes, err := hex.DecodeString(loadedProveInput.S)
-	if err != nil {
-		return fmt.Errorf("error decoding S hex: %w", err)
+
+	// Verify
+	if artifacts.PublicInputs() {
+		err = zkecdsa.VerifyWithPublicInputs(proofLoaded, artifacts.VK, loadedProveInput)
+	} else {
+		err = zkecdsa.Verify(proofLoaded, artifacts.VK, publicWitnessLoaded)
 	}
-	msgHashBytes, err := hex.DecodeString(loadedProveInput.MsgHash)
 	if err != nil {
-		return fmt.Errorf("error decoding MsgHash hex: %w", err)
+		return fmt.Errorf("verification FAILED: %w", err)
 	}
-	pubXBytes, err := hex.DecodeString(loadedProveInput.PubX)
-	if err != nil {
-		return fmt.Errorf("error decoding PubX hex: %w", err)
+	fmt.Println("Verification SUCCEEDED!")
+	fmt.Println("ReadFromFile test PASSED. Loaded artifacts are valid and functional.")
+
+	return nil
+}
+
+// Helper function to copy a C buffer into a Go byte slice
+func cBytesToGoBytes(ptr *C.uchar, length C.size_t) []byte {
+	if ptr == nil || length == 0 {
+		return nil
 	}
-	pubYBytes, err := hex.DecodeString(loadedProveInput.PubY)
-	if err != nil {
-		return fmt.Errorf("error decoding PubY hex: %w", err)
+	return C.GoBytes(unsafe.Pointer(ptr), C.int(length))
+}
+
+// Helper function to convert the C input struct to the Go input struct
+func proveInputFromC(input C.ProveInput) *ProveInputEcdsa {
+	return &ProveInputEcdsa{
+		MsgHash:   cStringToGoString(input.msgHash),
+		R:         cStringToGoString(input.r),
+		S:         cStringToGoString(input.s),
+		Signature: cStringToGoString(input.signature),
+		PubX:      cStringToGoString(input.pubX),
+		PubY:      cStringToGoString(input.pubY),
+		PublicKey: cStringToGoString(input.publicKey),
+		Curve:     zkecdsa.Curve(cStringToGoString(input.curve)),
+	}
+}
+
+// Helper function to convert the C paths struct to Go paths (NULL fields stay unset)
+func pathsFromC(paths C.ArtifactPaths) zkecdsa.Paths {
+	return zkecdsa.Paths{
+		Dir:          cStringToGoString(paths.dir),
+		Curve:        zkecdsa.Curve(cStringToGoString(paths.curve)),
+		R1CS:         cStringToGoString(paths.r1cs),
+		ProvingKey:   cStringToGoString(paths.proving_key),
+		VerifyingKey: cStringToGoString(paths.verifying_key),
+		WitnessInput: cStringToGoString(paths.witness_input),
+		Manifest:     cStringToGoString(paths.manifest),
+		Circuit:      zkecdsa.CircuitType(cStringToGoString(paths.circuit)),
+		BatchSize:    int(paths.batch_size),
 	}
+}
 
-	rLoaded := new(big.Int).SetBytes(rBytes)
-	sLoaded := new(big.Int).SetBytes(sBytes)
-	pubXLoaded := new(big.Int).SetBytes(pubXBytes)
-	pubYLoaded := new(big.Int).SetBytes(pubYBytes)
+// Helper function to convert the C membership input to the Go membership
+// input. A NULL path yields path_len empty siblings, enough to verify
+func membershipInputFromC(input C.MembershipProveInput) *zkecdsa.MembershipInput {
+	membership := &zkecdsa.MembershipInput{
+		ProveInputEcdsa: *proveInputFromC(input.input),
+		Root:            cStringToGoString(input.root),
+		Path:            make([]string, int(input.path_len)),
+		Index:           int(input.index),
+	}
+	if input.path != nil {
+		for i, sibling := range unsafe.Slice(input.path, int(input.path_len)) {
+			membership.Path[i] = cStringToGoString(sibling)
+		}
+	}
+	return membership
+}
 
-	// 5. Create a new witness using the loaded input data
-	witnessCircuitLoaded := EcdsaCircuit[emulated.P256Fp, emulated.P256Fr]{
-		Sig: gnarkecdsa.Signature[emulated.P256Fr]{
-			R: emulated.ValueOf[emulated.P256Fr](rLoaded),
-			S: emulated.ValueOf[emulated.P256Fr](sLoaded),
-		},
-		Msg: emulated.ValueOf[emulated.P256Fr](msgHashBytes),
-		Pub: gnarkecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]{
-			X: emulated.ValueOf[emulated.P256Fp](pubXLoaded),
-			Y: emulated.ValueOf[emulated.P256Fp](pubYLoaded),
-		},
+// Helper function to convert a C array of count inputs to the Go batch input,
+// on the curve of the first input
+func batchInputFromC(inputs *C.ProveInput, count C.size_t) (*zkecdsa.BatchInput, error) {
+	if inputs == nil || count == 0 {
+		return nil, fmt.Errorf("%w: empty batch", zkecdsa.ErrInvalidInput)
 	}
+	batch := &zkecdsa.BatchInput{}
+	for _, input := range unsafe.Slice(inputs, int(count)) {
+		batch.Inputs = append(batch.Inputs, *proveInputFromC(input))
+	}
+	batch.Curve = batch.Inputs[0].Curve
+	return batch, nil
+}
 
-	witnessFullLoaded, err := frontend.NewWitness(&witnessCircuitLoaded, ecc.BN254.ScalarField())
-	if err != nil {
-		return fmt.Errorf("error creating full witness from loaded data: %w", err)
+// Helper function to convert the C assertion struct to the Go WebAuthn input
+func webAuthnInputFromC(assertion C.WebAuthnAssertion) *zkecdsa.WebAuthnInput {
+	input := zkecdsa.NewWebAuthnInput(
+		cBytesToGoBytes(assertion.authenticator_data, assertion.authenticator_data_len),
+		cBytesToGoBytes(assertion.client_data_json, assertion.client_data_json_len),
+		cBytesToGoBytes(assertion.signature, assertion.signature_len),
+		cStringToGoString(assertion.pubX),
+		cStringToGoString(assertion.pubY),
+		zkecdsa.Curve(cStringToGoString(assertion.curve)),
+	)
+	if challenge := cBytesToGoBytes(assertion.challenge, assertion.challenge_len); challenge != nil {
+		input.Challenge = base64.RawURLEncoding.EncodeToString(challenge)
 	}
+	return input
+}
 
-	publicWitnessLoaded, err := witnessFullLoaded.Public()
-	if err != nil {
-		return fmt.Errorf("error getting public witness from loaded data: %w", err)
+// Helper function to build a failed ProofResult (caller must free)
+func errorResult(err error) C.ProofResult {
+	return C.ProofResult{
+		error_msg:  goStringToCString(err.Error()),
+		success:    0,
+		error_code: errorCode(err),
 	}
+}
 
-	// 6. Perform proof and verification
-	fmt.Println("\n--- Proving and Verifying with loaded artifacts ---")
+// errorCode maps an error to its EcdsaErrorCode, the most specific input
+// error first
+func errorCode(err error) C.EcdsaErrorCode {
+	switch {
+	case errors.Is(err, zkecdsa.ErrSignatureInvalid):
+		return C.ECDSA_ERR_SIGNATURE_INVALID
+	case errors.Is(err, zkecdsa.ErrHighS):
+		return C.ECDSA_ERR_HIGH_S
+	case errors.Is(err, zkecdsa.ErrScalarOutOfRange):
+		return C.ECDSA_ERR_INVALID_SCALAR
+	case errors.Is(err, zkecdsa.ErrPointNotOnCurve):
+		return C.ECDSA_ERR_INVALID_POINT
+	case errors.Is(err, zkecdsa.ErrInvalidLength):
+		return C.ECDSA_ERR_INVALID_LENGTH
+	case errors.Is(err, zkecdsa.ErrInvalidHex):
+		return C.ECDSA_ERR_INVALID_HEX
+	case errors.Is(err, zkecdsa.ErrInvalidInput):
+		return C.ECDSA_ERR_INVALID_INPUT
+	case errors.Is(err, zkecdsa.ErrArtifactMissing):
+		return C.ECDSA_ERR_ARTIFACT_MISSING
+	case errors.Is(err, zkecdsa.ErrArtifactCorrupt):
+		return C.ECDSA_ERR_ARTIFACT_CORRUPT
+	case errors.Is(err, zkecdsa.ErrVerificationFailed):
+		return C.ECDSA_ERR_VERIFICATION_FAILED
+	case errors.Is(err, zkecdsa.ErrProvingFailed):
+		return C.ECDSA_ERR_PROVING_FAILED
+	case errors.Is(err, errPanic):
+		return C.ECDSA_ERR_PANIC
+	default:
+		return C.ECDSA_ERR_INTERNAL
+	}
+}
+
+// Error of a panic recovered at the cgo boundary, reported as ECDSA_ERR_PANIC
+var errPanic = errors.New("panic")
+
+// Stack trace of the last panic recovered by an export, see EcdsaLastPanicTrace
+var (
+	lastPanicMu    sync.Mutex
+	lastPanicTrace string
+)
+
+// Helper function deferred by the exports returning a ProofResult: a panic
+// becomes a failed result instead of aborting the host process
+func recoverResult(result *C.ProofResult) {
+	if r := recover(); r != nil {
+		*result = panicResult(r)
+	}
+}
+
+// Helper function deferred by the exports without a result: a panic is
+// only recorded
+func recoverPanic() {
+	if r := recover(); r != nil {
+		recordPanic(r)
+	}
+}
+
+// Helper function to build the failed ProofResult of a recovered panic (caller must free)
+func panicResult(r interface{}) C.ProofResult {
+	recordPanic(r)
+	return errorResult(fmt.Errorf("%w: %v", errPanic, r))
+}
+
+// Helper function to record the stack trace of a recovered panic, called
+// from the deferred function so the trace includes the panicking frames
+func recordPanic(r interface{}) {
+	trace := fmt.Sprintf("panic: %v\n\n%s", r, debug.Stack())
+	lastPanicMu.Lock()
+	defer lastPanicMu.Unlock()
+	lastPanicTrace = trace
+}
+
+// Environment variable enabling the low-S policy of the proving exports:
+// "1" rejects signatures with S above n/2, see zkecdsa.ValidationPolicy
+const envRequireLowS = "ECDSA_REQUIRE_LOW_S"
+
+// Helper function returning the validation policy of the proving exports
+func validationPolicy() zkecdsa.ValidationPolicy {
+	return zkecdsa.ValidationPolicy{RequireLowS: os.Getenv(envRequireLowS) == "1"}
+}
+
+// Core proof generation with custom inputs
+func performProofVerificationWithInputs(proveInput *ProveInputEcdsa) error {
+	paths, err := artifactPathsFor(zkecdsa.CircuitECDSA, proveInput.Curve)
+	if err != nil {
+		return err
+	}
+	artifacts, err := zkecdsa.LoadArtifacts(paths)
+	if err != nil {
+		return err
+	}
+	proveInput.Curve = paths.Curve
 
 	// Prove
-	startProveLoaded := time.Now()
-	proofLoaded, err := groth16.Prove(loadedR1CS, loadedPK, witnessFullLoaded)
+	proofLoaded, publicWitnessLoaded, err := artifacts.Prover().WithValidationPolicy(validationPolicy()).Prove(proveInput)
 	if err != nil {
-		return fmt.Errorf("error generating proof: %w", err)
+		return err
 	}
-	fmt.Printf("Proof generated (%.1fms).\n", float64(time.Since(startProveLoaded).Milliseconds()))
 
 	// Verify
-	startVerifyLoaded := time.Now()
-	err = groth16.Verify(proofLoaded, loadedVK, publicWitnessLoaded)
+	if artifacts.PublicInputs() {
+		return zkecdsa.VerifyWithPublicInputs(proofLoaded, artifacts.VK, proveInput)
+	}
+	return zkecdsa.Verify(proofLoaded, artifacts.VK, publicWitnessLoaded)
+}
+
+// Helper function to build a successful ProofResult carrying a proof (caller must free)
+func proofBytesResult(proofBytes, publicWitnessBytes []byte) C.ProofResult {
+	return C.ProofResult{
+		error_msg:          nil,
+		success:            1,
+		proof:              (*C.uchar)(C.CBytes(proofBytes)),
+		proof_len:          C.size_t(len(proofBytes)),
+		public_witness:     (*C.uchar)(C.CBytes(publicWitnessBytes)),
+		public_witness_len: C.size_t(len(publicWitnessBytes)),
+	}
+}
+
+// Proof generation only: returns the serialized proof and public witness
+func generateProofBytes(proveInput *ProveInputEcdsa) ([]byte, []byte, error) {
+	paths, err := artifactPathsFor(zkecdsa.CircuitECDSA, proveInput.Curve)
 	if err != nil {
-		return fmt.Errorf("verification FAILED: %w", err)
+		return nil, nil, err
 	}
-	fmt.Printf("Verification SUCCEEDED (%.1fms)!\n", float64(time.Since(startVerifyLoaded).Milliseconds()))
-	fmt.Println("ReadFromFile test PASSED. Loaded artifacts are valid and functional.")
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proveToBytes(prover, proveInput)
+}
 
-	return nil
+// WebAuthn proof generation only: returns the serialized proof and public witness
+func generateWebAuthnProofBytes(input *zkecdsa.WebAuthnInput) ([]byte, []byte, error) {
+	paths, err := artifactPathsFor(zkecdsa.CircuitWebAuthn, input.Curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proveToBytes(prover, input)
 }
 
-// Core proof generation with custom inputs
-func performProofVerificationWithInputs(proveInput *ProveInputEcdsa) error {
-	// 1. Read back the compiled circuit
-	loadedR1CS := groth16.NewCS(ecc.BN254)
-	err := readFromFile("r1cs.bin", loadedR1CS)
+// Batch proof generation only: returns the serialized proof and public witness
+func generateBatchProofBytes(input *zkecdsa.BatchInput) ([]byte, []byte, error) {
+	paths, err := batchArtifactPathsFor(len(input.Inputs), input.Curve)
 	if err != nil {
-		return fmt.Errorf("error reading r1cs.bin: %w", err)
+		return nil, nil, err
 	}
+	prover, err := zkecdsa.NewProver(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proveToBytes(prover, input)
+}
 
-	// 2. Read back the proving key
-	loadedPK := groth16.NewProvingKey(ecc.BN254)
-	err = readFromFile("proving_key.bin", loadedPK)
+// Membership proof generation only: returns the serialized proof and public witness
+func generateMembershipProofBytes(input *zkecdsa.MembershipInput) ([]byte, []byte, error) {
+	paths, err := artifactPathsFor(zkecdsa.CircuitMembership, input.Curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	prover, err := zkecdsa.NewProver(paths)
 	if err != nil {
-		return fmt.Errorf("error reading proving_key.bin: %w", err)
+		return nil, nil, err
 	}
+	return proveToBytes(prover, input)
+}
 
-	// 3. Read back the verifying key
-	loadedVK := groth16.NewVerifyingKey(ecc.BN254)
-	err = readFromFile("verifying_key.bin", loadedVK)
+// Proof generation with an already loaded prover
+func proveToBytes(prover *zkecdsa.Prover, proveInput zkecdsa.Input) ([]byte, []byte, error) {
+	proof, publicWitness, err := prover.WithValidationPolicy(validationPolicy()).Prove(proveInput)
+	if err != nil {
+		return nil, nil, err
+	}
+	proofBytes, err := zkecdsa.MarshalProof(proof)
 	if err != nil {
-		return fmt.Errorf("error reading verifying_key.bin: %w", err)
+		return nil, nil, err
 	}
+	publicWitnessBytes, err := zkecdsa.MarshalPublicWitness(publicWitness)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proofBytes, publicWitnessBytes, nil
+}
 
-	// Decode hex strings
-	rBytes, err := hex.DecodeString(proveInput.R)
+// Helper function to decode a verifying key and a proof of the same backend,
+// the backend being detected from the verifying key
+func unmarshalProofAndKey(proofBytes, vkBytes []byte) (zkecdsa.Proof, zkecdsa.VerifyingKey, error) {
+	vk, err := zkecdsa.UnmarshalVerifyingKey(vkBytes)
 	if err != nil {
-		return fmt.Errorf("error decoding R hex: %w", err)
+		return nil, nil, err
 	}
-	sBytes, err := hex.DecodeString(proveInput.S)
+	backend, err := zkecdsa.BackendOf(vk)
 	if err != nil {
-		return fmt.Errorf("error decoding S hex: %w", err)
+		return nil, nil, err
 	}
-	msgHashBytes, err := hex.DecodeString(proveInput.MsgHash)
+	proof, err := zkecdsa.UnmarshalProof(backend, proofBytes)
 	if err != nil {
-		return fmt.Errorf("error decoding MsgHash hex: %w", err)
+		return nil, nil, err
 	}
-	pubXBytes, err := hex.DecodeString(proveInput.PubX)
+	return proof, vk, nil
+}
+
+// Verification only: checks serialized proof bytes against a serialized public
+// witness and verifying key, without touching the proving artifacts
+func verifyProofBytes(proofBytes, publicWitnessBytes, vkBytes []byte) error {
+	proof, vk, err := unmarshalProofAndKey(proofBytes, vkBytes)
 	if err != nil {
-		return fmt.Errorf("error decoding PubX hex: %w", err)
+		return err
 	}
-	pubYBytes, err := hex.DecodeString(proveInput.PubY)
+	publicWitness, err := zkecdsa.UnmarshalPublicWitness(publicWitnessBytes)
 	if err != nil {
-		return fmt.Errorf("error decoding PubY hex: %w", err)
+		return err
 	}
+	return zkecdsa.Verify(proof, vk, publicWitness)
+}
 
-	rLoaded := new(big.Int).SetBytes(rBytes)
-	sLoaded := new(big.Int).SetBytes(sBytes)
-	pubXLoaded := new(big.Int).SetBytes(pubXBytes)
-	pubYLoaded := new(big.Int).SetBytes(pubYBytes)
+// Verification only: rebuilds the public witness from the message hash and
+// public key of proveInput (EcdsaPublicCircuit artifacts)
+func verifyProofBytesWithInputs(proofBytes []byte, proveInput *ProveInputEcdsa, vkBytes []byte) error {
+	paths, err := artifactPathsFor(zkecdsa.CircuitECDSA, proveInput.Curve)
+	if err != nil {
+		return err
+	}
+	withCurve := *proveInput
+	withCurve.Curve = paths.Curve
+	proveInput = &withCurve
 
-	// Create witness
-	witnessCircuitLoaded := EcdsaCircuit[emulated.P256Fp, emulated.P256Fr]{
-		Sig: gnarkecdsa.Signature[emulated.P256Fr]{
-			R: emulated.ValueOf[emulated.P256Fr](rLoaded),
-			S: emulated.ValueOf[emulated.P256Fr](sLoaded),
-		},
-		Msg: emulated.ValueOf[emulated.P256Fr](msgHashBytes),
-		Pub: gnarkecdsa.PublicKey[emulated.P256Fp, emulated.P256Fr]{
-			X: emulated.ValueOf[emulated.P256Fp](pubXLoaded),
-			Y: emulated.ValueOf[emulated.P256Fp](pubYLoaded),
-		},
+	proof, vk, err := unmarshalProofAndKey(proofBytes, vkBytes)
+	if err != nil {
+		return err
 	}
+	return zkecdsa.VerifyWithPublicInputs(proof, vk, proveInput)
+}
 
-	witnessFullLoaded, err := frontend.NewWitness(&witnessCircuitLoaded, ecc.BN254.ScalarField())
+// Verification only: rebuilds the WebAuthn public witness from the challenge
+// and public key of input
+func verifyWebAuthnProofBytes(proofBytes []byte, input *zkecdsa.WebAuthnInput, vkBytes []byte) error {
+	paths, err := artifactPathsFor(zkecdsa.CircuitWebAuthn, input.Curve)
 	if err != nil {
-		return fmt.Errorf("error creating full witness: %w", err)
+		return err
 	}
+	withCurve := *input
+	withCurve.Curve = paths.Curve
+	input = &withCurve
 
-	publicWitnessLoaded, err := witnessFullLoaded.Public()
+	proof, vk, err := unmarshalProofAndKey(proofBytes, vkBytes)
 	if err != nil {
-		return fmt.Errorf("error getting public witness: %w", err)
+		return err
 	}
+	return zkecdsa.VerifyWithPublicInputs(proof, vk, input)
+}
 
-	// Prove
-	proofLoaded, err := groth16.Prove(loadedR1CS, loadedPK, witnessFullLoaded)
+// Verification only: rebuilds the batch public witness, the commitment, from
+// the message hashes and public keys of input with the commitment hash
+// recorded in the manifest of the batch artifacts
+func verifyBatchProofBytes(proofBytes []byte, input *zkecdsa.BatchInput, vkBytes []byte) error {
+	paths, err := batchArtifactPathsFor(len(input.Inputs), input.Curve)
+	if err != nil {
+		return err
+	}
+	manifest, err := zkecdsa.ReadManifest(paths.Manifest, paths.Circuit, paths.Curve)
 	if err != nil {
-		return fmt.Errorf("error generating proof: %w", err)
+		return err
 	}
+	withShape := *input
+	withShape.Curve, withShape.CommitmentHash = paths.Curve, manifest.CommitmentHash
+	input = &withShape
 
-	// Verify
-	err = groth16.Verify(proofLoaded, loadedVK, publicWitnessLoaded)
+	proof, vk, err := unmarshalProofAndKey(proofBytes, vkBytes)
 	if err != nil {
-		return fmt.Errorf("verification failed: %w", err)
+		return err
 	}
+	return zkecdsa.VerifyWithPublicInputs(proof, vk, input)
+}
 
-	return nil
+// Verification only: rebuilds the membership public witness from the message
+// hash and key tree root of input
+func verifyMembershipProofBytes(proofBytes []byte, input *zkecdsa.MembershipInput, vkBytes []byte) error {
+	paths, err := artifactPathsFor(zkecdsa.CircuitMembership, input.Curve)
+	if err != nil {
+		return err
+	}
+	withCurve := *input
+	withCurve.Curve = paths.Curve
+	input = &withCurve
+
+	proof, vk, err := unmarshalProofAndKey(proofBytes, vkBytes)
+	if err != nil {
+		return err
+	}
+	return zkecdsa.VerifyWithPublicInputs(proof, vk, input)
+}
+
+//export SetArtifactPaths
+func SetArtifactPaths(paths C.ArtifactPaths) {
+	defer recoverPanic()
+	artifactPathsMu.Lock()
+	defer artifactPathsMu.Unlock()
+	artifactPaths = pathsFromC(paths)
 }
 
 //export RunProofVerification
-func RunProofVerification() C.ProofResult {
+func RunProofVerification() (result C.ProofResult) {
+	defer recoverResult(&result)
 	err := performProofVerification()
 	if err != nil {
-		return C.ProofResult{
-			error_msg: goStringToCString(err.Error()),
-			success:   0,
-		}
+		return errorResult(err)
 	}
 	return C.ProofResult{
 		error_msg: nil,
@@ -391,22 +668,92 @@ func RunProofVerification() C.ProofResult {
 }
 
 //export RunProofVerificationWithInputs
-func RunProofVerificationWithInputs(input C.ProveInput) C.ProofResult {
+func RunProofVerificationWithInputs(input C.ProveInput) (result C.ProofResult) {
+	defer recoverResult(&result)
 	// Convert C input to Go struct
-	proveInput := &ProveInputEcdsa{
-		MsgHash: cStringToGoString(input.msgHash),
-		R:       cStringToGoString(input.r),
-		S:       cStringToGoString(input.s),
-		PubX:    cStringToGoString(input.pubX),
-		PubY:    cStringToGoString(input.pubY),
-	}
+	proveInput := proveInputFromC(input)
 
 	err := performProofVerificationWithInputs(proveInput)
 	if err != nil {
-		return C.ProofResult{
-			error_msg: goStringToCString(err.Error()),
-			success:   0,
-		}
+		return errorResult(err)
+	}
+	return C.ProofResult{
+		error_msg: nil,
+		success:   1,
+	}
+}
+
+//export GenerateProof
+func GenerateProof(input C.ProveInput) (result C.ProofResult) {
+	defer recoverResult(&result)
+	proofBytes, publicWitnessBytes, err := generateProofBytes(proveInputFromC(input))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export GenerateWebAuthnProof
+func GenerateWebAuthnProof(assertion C.WebAuthnAssertion) (result C.ProofResult) {
+	defer recoverResult(&result)
+	proofBytes, publicWitnessBytes, err := generateWebAuthnProofBytes(webAuthnInputFromC(assertion))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export GenerateBatchProof
+func GenerateBatchProof(inputs *C.ProveInput, count C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	batch, err := batchInputFromC(inputs, count)
+	if err != nil {
+		return errorResult(err)
+	}
+	proofBytes, publicWitnessBytes, err := generateBatchProofBytes(batch)
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export GenerateMembershipProof
+func GenerateMembershipProof(input C.MembershipProveInput) (result C.ProofResult) {
+	defer recoverResult(&result)
+	proofBytes, publicWitnessBytes, err := generateMembershipProofBytes(membershipInputFromC(input))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export VerifyProof
+func VerifyProof(proof *C.uchar, proofLen C.size_t, publicWitness *C.uchar, publicWitnessLen C.size_t, vk *C.uchar, vkLen C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	err := verifyProofBytes(
+		cBytesToGoBytes(proof, proofLen),
+		cBytesToGoBytes(publicWitness, publicWitnessLen),
+		cBytesToGoBytes(vk, vkLen),
+	)
+	if err != nil {
+		return errorResult(err)
+	}
+	return C.ProofResult{
+		error_msg: nil,
+		success:   1,
+	}
+}
+
+//export VerifyProofWithInputs
+func VerifyProofWithInputs(proof *C.uchar, proofLen C.size_t, input C.ProveInput, vk *C.uchar, vkLen C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	err := verifyProofBytesWithInputs(
+		cBytesToGoBytes(proof, proofLen),
+		proveInputFromC(input),
+		cBytesToGoBytes(vk, vkLen),
+	)
+	if err != nil {
+		return errorResult(err)
 	}
 	return C.ProofResult{
 		error_msg: nil,
@@ -414,18 +761,372 @@ func RunProofVerificationWithInputs(input C.ProveInput) C.ProofResult {
 	}
 }
 
+//export VerifyWebAuthnProof
+func VerifyWebAuthnProof(proof *C.uchar, proofLen C.size_t, assertion C.WebAuthnAssertion, vk *C.uchar, vkLen C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	err := verifyWebAuthnProofBytes(
+		cBytesToGoBytes(proof, proofLen),
+		webAuthnInputFromC(assertion),
+		cBytesToGoBytes(vk, vkLen),
+	)
+	if err != nil {
+		return errorResult(err)
+	}
+	return C.ProofResult{
+		error_msg: nil,
+		success:   1,
+	}
+}
+
+//export VerifyBatchProofWithInputs
+func VerifyBatchProofWithInputs(proof *C.uchar, proofLen C.size_t, inputs *C.ProveInput, count C.size_t, vk *C.uchar, vkLen C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	batch, err := batchInputFromC(inputs, count)
+	if err != nil {
+		return errorResult(err)
+	}
+	err = verifyBatchProofBytes(
+		cBytesToGoBytes(proof, proofLen),
+		batch,
+		cBytesToGoBytes(vk, vkLen),
+	)
+	if err != nil {
+		return errorResult(err)
+	}
+	return C.ProofResult{
+		error_msg: nil,
+		success:   1,
+	}
+}
+
+//export VerifyMembershipProof
+func VerifyMembershipProof(proof *C.uchar, proofLen C.size_t, input C.MembershipProveInput, vk *C.uchar, vkLen C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	err := verifyMembershipProofBytes(
+		cBytesToGoBytes(proof, proofLen),
+		membershipInputFromC(input),
+		cBytesToGoBytes(vk, vkLen),
+	)
+	if err != nil {
+		return errorResult(err)
+	}
+	return C.ProofResult{
+		error_msg: nil,
+		success:   1,
+	}
+}
+
+// Provers of the live handles returned by EcdsaProverNew. Handles are never
+// reused, so a released or forged handle is reported instead of reaching
+// another prover
+var (
+	proversMu  sync.Mutex
+	provers    = make(map[C.uintptr_t]*zkecdsa.Prover)
+	lastHandle C.uintptr_t
+)
+
+// Helper function registering prover under a new handle
+func registerProver(prover *zkecdsa.Prover) C.uintptr_t {
+	proversMu.Lock()
+	defer proversMu.Unlock()
+	lastHandle++
+	provers[lastHandle] = prover
+	return lastHandle
+}
+
+// Helper function returning the prover of a live handle
+func proverFromHandle(handle C.uintptr_t) (*zkecdsa.Prover, error) {
+	proversMu.Lock()
+	defer proversMu.Unlock()
+	prover, ok := provers[handle]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid or released prover handle", zkecdsa.ErrInvalidInput)
+	}
+	return prover, nil
+}
+
+//export EcdsaProverNew
+func EcdsaProverNew(paths C.ArtifactPaths, status *C.ProofResult) (handle C.uintptr_t) {
+	defer func() {
+		if r := recover(); r != nil {
+			handle = 0
+			if status != nil {
+				*status = panicResult(r)
+			}
+		}
+	}()
+	prover, err := zkecdsa.NewProver(pathsFromC(paths))
+	if err != nil {
+		if status != nil {
+			*status = errorResult(err)
+		}
+		return 0
+	}
+	if status != nil {
+		*status = C.ProofResult{success: 1}
+	}
+	return registerProver(prover)
+}
+
+//export EcdsaProve
+func EcdsaProve(handle C.uintptr_t, input C.ProveInput) (result C.ProofResult) {
+	defer recoverResult(&result)
+	prover, err := proverFromHandle(handle)
+	if err != nil {
+		return errorResult(err)
+	}
+	proofBytes, publicWitnessBytes, err := proveToBytes(prover, proveInputFromC(input))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export EcdsaProveWebAuthn
+func EcdsaProveWebAuthn(handle C.uintptr_t, assertion C.WebAuthnAssertion) (result C.ProofResult) {
+	defer recoverResult(&result)
+	prover, err := proverFromHandle(handle)
+	if err != nil {
+		return errorResult(err)
+	}
+	proofBytes, publicWitnessBytes, err := proveToBytes(prover, webAuthnInputFromC(assertion))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export EcdsaProveBatch
+func EcdsaProveBatch(handle C.uintptr_t, inputs *C.ProveInput, count C.size_t) (result C.ProofResult) {
+	defer recoverResult(&result)
+	prover, err := proverFromHandle(handle)
+	if err != nil {
+		return errorResult(err)
+	}
+	batch, err := batchInputFromC(inputs, count)
+	if err != nil {
+		return errorResult(err)
+	}
+	proofBytes, publicWitnessBytes, err := proveToBytes(prover, batch)
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export EcdsaProveMembership
+func EcdsaProveMembership(handle C.uintptr_t, input C.MembershipProveInput) (result C.ProofResult) {
+	defer recoverResult(&result)
+	prover, err := proverFromHandle(handle)
+	if err != nil {
+		return errorResult(err)
+	}
+	proofBytes, publicWitnessBytes, err := proveToBytes(prover, membershipInputFromC(input))
+	if err != nil {
+		return errorResult(err)
+	}
+	return proofBytesResult(proofBytes, publicWitnessBytes)
+}
+
+//export EcdsaProverFree
+func EcdsaProverFree(handle C.uintptr_t) {
+	defer recoverPanic()
+	proversMu.Lock()
+	defer proversMu.Unlock()
+	delete(provers, handle)
+}
+
+//export EcdsaLastPanicTrace
+func EcdsaLastPanicTrace() *C.char {
+	defer recoverPanic()
+	lastPanicMu.Lock()
+	defer lastPanicMu.Unlock()
+	if lastPanicTrace == "" {
+		return nil
+	}
+	return goStringToCString(lastPanicTrace)
+}
+
+//export EcdsaFreeString
+func EcdsaFreeString(str *C.char) {
+	defer recoverPanic()
+	if str != nil {
+		freeCString(str)
+	}
+}
+
+// Helper type routing the logs of zkecdsa to the callback set by
+// EcdsaSetLogCallback: the attributes of a record are encoded by a JSON
+// handler into the buffer of the sink, then passed to the callback with the
+// message and level
+type callbackHandler struct {
+	slog.Handler
+	sink *logSink
+}
+
+// Callback of EcdsaSetLogCallback, called with mu held so the host sees one
+// record at a time
+type logSink struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	callback C.EcdsaLogCallback
+	userData unsafe.Pointer
+}
+
+func newCallbackHandler(sink *logSink, minLevel slog.Level) *callbackHandler {
+	return &callbackHandler{
+		Handler: slog.NewJSONHandler(&sink.buf, &slog.HandlerOptions{
+			Level: minLevel,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}),
+		sink: sink,
+	}
+}
+
+func (h *callbackHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.sink.mu.Lock()
+	defer h.sink.mu.Unlock()
+	h.sink.buf.Reset()
+	if err := h.Handler.Handle(ctx, r); err != nil {
+		return err
+	}
+	message := goStringToCString(r.Message)
+	defer freeCString(message)
+	attrs := goStringToCString(strings.TrimSuffix(h.sink.buf.String(), "\n"))
+	defer freeCString(attrs)
+	C.callLogCallback(h.sink.callback, C.EcdsaLogLevel(r.Level), message, attrs, h.sink.userData)
+	return nil
+}
+
+func (h *callbackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &callbackHandler{Handler: h.Handler.WithAttrs(attrs), sink: h.sink}
+}
+
+func (h *callbackHandler) WithGroup(name string) slog.Handler {
+	return &callbackHandler{Handler: h.Handler.WithGroup(name), sink: h.sink}
+}
+
+//export EcdsaSetLogCallback
+func EcdsaSetLogCallback(callback C.EcdsaLogCallback, minLevel C.EcdsaLogLevel, userData unsafe.Pointer) {
+	defer recoverPanic()
+	if callback == nil {
+		zkecdsa.SetLogger(nil)
+		return
+	}
+	sink := &logSink{callback: callback, userData: userData}
+	zkecdsa.SetLogger(slog.New(newCallbackHandler(sink, slog.Level(minLevel))))
+}
+
+// Registry of the metrics enabled by EcdsaEnableMetrics, nil until then
+var (
+	metricsMu       sync.Mutex
+	metricsRegistry *prometheus.Registry
+)
+
+// Helper function deferred by the exports returning an error code: a panic
+// becomes ECDSA_ERR_PANIC instead of aborting the host process
+func recoverCode(code *C.EcdsaErrorCode) {
+	if r := recover(); r != nil {
+		recordPanic(r)
+		*code = C.ECDSA_ERR_PANIC
+	}
+}
+
+// Helper function returning the error code of err, logged for the host to
+// see the message the code cannot carry
+func logErrorCode(message string, err error) C.EcdsaErrorCode {
+	zkecdsa.Logger().Warn(message, "kind", zkecdsa.ErrorKind(err), "error", err)
+	return errorCode(err)
+}
+
+//export EcdsaEnableMetrics
+func EcdsaEnableMetrics() (code C.EcdsaErrorCode) {
+	defer recoverCode(&code)
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if metricsRegistry != nil {
+		return C.ECDSA_OK
+	}
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return logErrorCode("enabling metrics failed", err)
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return logErrorCode("enabling metrics failed", err)
+	}
+	if err := zkecdsa.EnableMetrics(registry); err != nil {
+		return logErrorCode("enabling metrics failed", err)
+	}
+	metricsRegistry = registry
+	return C.ECDSA_OK
+}
+
+//export EcdsaMetrics
+func EcdsaMetrics(metrics **C.char) (code C.EcdsaErrorCode) {
+	defer recoverCode(&code)
+	if metrics == nil {
+		return C.ECDSA_ERR_INVALID_INPUT
+	}
+	*metrics = nil
+	metricsMu.Lock()
+	registry := metricsRegistry
+	metricsMu.Unlock()
+	if registry == nil {
+		return logErrorCode("reading metrics failed", fmt.Errorf("%w: metrics not enabled, call EcdsaEnableMetrics first", zkecdsa.ErrInvalidInput))
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return logErrorCode("reading metrics failed", err)
+	}
+	var buf bytes.Buffer
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(&buf, family); err != nil {
+			return logErrorCode("reading metrics failed", err)
+		}
+	}
+	*metrics = goStringToCString(buf.String())
+	return C.ECDSA_OK
+}
+
 //export FreeProofResult
 func FreeProofResult(result C.ProofResult) {
+	defer recoverPanic()
 	if result.error_msg != nil {
 		freeCString(result.error_msg)
 	}
+	if result.proof != nil {
+		C.free(unsafe.Pointer(result.proof))
+	}
+	if result.public_witness != nil {
+		C.free(unsafe.Pointer(result.public_witness))
+	}
+}
+
+// Helper function for the demo: reports whether a public witness carries the
+// message hash and public key, i.e. comes from EcdsaPublicCircuit artifacts
+func variantIsPublic(publicWitnessBytes []byte) bool {
+	publicWitness, err := zkecdsa.UnmarshalPublicWitness(publicWitnessBytes)
+	if err != nil {
+		return false
+	}
+	vector, ok := publicWitness.Vector().(fr.Vector)
+	return ok && len(vector) > 0
 }
 
 // Go main function for testing
 func main() {
-	// Test the C export functions
+	// Test the C export functions, logging the timings of the library
 	fmt.Println("Testing cGO ECDSA Proof Verifier...")
-	
+	zkecdsa.SetLogger(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if code := EcdsaEnableMetrics(); code != C.ECDSA_OK {
+		fmt.Printf("✗ EcdsaEnableMetrics returned error code %d\n", code)
+	}
+
 	// Test 1: Run proof verification from files
 	fmt.Println("\n=== Test 1: RunProofVerification ===")
 	result1 := RunProofVerification()
@@ -438,15 +1139,19 @@ func main() {
 
 	// Test 2: Run proof verification with custom inputs (generating variant input)
 	fmt.Println("\n=== Test 2: RunProofVerificationWithInputs ===")
-	var loadedProveInput ProveInputEcdsa
-	err := readFromFile("witness_input.json", &loadedProveInput)
+	paths, err := artifactPathsFor(zkecdsa.CircuitECDSA, "")
 	if err != nil {
-		fmt.Printf("✗ Error reading witness_input.json for test: %v\n", err)
+		fmt.Printf("✗ Error resolving artifact paths: %v\n", err)
+		return
+	}
+	loadedProveInput, err := zkecdsa.ReadInput(paths.WitnessInput)
+	if err != nil {
+		fmt.Printf("✗ Error reading witness input for test: %v\n", err)
 		return
 	}
 
 	// Generate a variant input for this execution
-	variantProveInput := createVariantProveInput(&loadedProveInput)
+	variantProveInput := createVariantProveInput(loadedProveInput)
 
 	fmt.Println("\n--- Generated NEW VALID ECDSA ProveInput for this execution ---")
 	fmt.Printf("MsgHash: %s\n", variantProveInput.MsgHash)
@@ -464,6 +1169,7 @@ func main() {
 		s:       goStringToCString(variantProveInput.S),
 		pubX:    goStringToCString(variantProveInput.PubX),
 		pubY:    goStringToCString(variantProveInput.PubY),
+		curve:   goStringToCString(string(variantProveInput.Curve)),
 	}
 
 	result2 := RunProofVerificationWithInputs(cInput)
@@ -473,13 +1179,118 @@ func main() {
 		fmt.Printf("✗ RunProofVerificationWithInputs failed: %s\n", cStringToGoString(result2.error_msg))
 	}
 
+	// Test 3: Prove and verify separately, exchanging only proof bytes
+	fmt.Println("\n=== Test 3: GenerateProof + VerifyProof ===")
+	result3 := GenerateProof(cInput)
+	if result3.success == 1 {
+		fmt.Printf("✓ GenerateProof succeeded (proof: %d bytes, public witness: %d bytes)\n", result3.proof_len, result3.public_witness_len)
+		vkBytes, err := os.ReadFile(paths.VerifyingKey)
+		if err != nil {
+			fmt.Printf("✗ Error reading %s for test: %v\n", paths.VerifyingKey, err)
+			return
+		}
+		cVK := (*C.uchar)(C.CBytes(vkBytes))
+		result4 := VerifyProof(result3.proof, result3.proof_len, result3.public_witness, result3.public_witness_len, cVK, C.size_t(len(vkBytes)))
+		if result4.success == 1 {
+			fmt.Println("✓ VerifyProof succeeded")
+		} else {
+			fmt.Printf("✗ VerifyProof failed: %s\n", cStringToGoString(result4.error_msg))
+		}
+		FreeProofResult(result4)
+
+		if variantIsPublic(cBytesToGoBytes(result3.public_witness, result3.public_witness_len)) {
+			result5 := VerifyProofWithInputs(result3.proof, result3.proof_len, cInput, cVK, C.size_t(len(vkBytes)))
+			if result5.success == 1 {
+				fmt.Println("✓ VerifyProofWithInputs succeeded")
+			} else {
+				fmt.Printf("✗ VerifyProofWithInputs failed: %s\n", cStringToGoString(result5.error_msg))
+			}
+			FreeProofResult(result5)
+		}
+		C.free(unsafe.Pointer(cVK))
+	} else {
+		fmt.Printf("✗ GenerateProof failed: %s\n", cStringToGoString(result3.error_msg))
+	}
+	FreeProofResult(result3)
+
+	// Test 4: Keep the artifacts loaded behind a handle and prove concurrently
+	fmt.Println("\n=== Test 4: EcdsaProverNew + EcdsaProve (2 goroutines) ===")
+	var status C.ProofResult
+	handle := EcdsaProverNew(C.ArtifactPaths{}, &status)
+	if handle != 0 {
+		var wg sync.WaitGroup
+		results := make([]C.ProofResult, 2)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = EcdsaProve(handle, cInput)
+			}(i)
+		}
+		wg.Wait()
+		for _, result := range results {
+			if result.success == 1 {
+				fmt.Println("✓ EcdsaProve succeeded")
+			} else {
+				fmt.Printf("✗ EcdsaProve failed: %s\n", cStringToGoString(result.error_msg))
+			}
+			FreeProofResult(result)
+		}
+		EcdsaProverFree(handle)
+
+		// Test 5: A released handle is reported as an invalid input
+		fmt.Println("\n=== Test 5: EcdsaProve on a released handle ===")
+		result := EcdsaProve(handle, cInput)
+		if result.error_code == C.ECDSA_ERR_INVALID_INPUT {
+			fmt.Printf("✓ EcdsaProve rejected the handle: %s\n", cStringToGoString(result.error_msg))
+		} else {
+			fmt.Printf("✗ EcdsaProve returned error code %d\n", result.error_code)
+		}
+		FreeProofResult(result)
+	} else {
+		fmt.Printf("✗ EcdsaProverNew failed: %s\n", cStringToGoString(status.error_msg))
+	}
+	FreeProofResult(status)
+
 	// Clean up
 	freeCString(cInput.msgHash)
 	freeCString(cInput.r)
 	freeCString(cInput.s)
 	freeCString(cInput.pubX)
 	freeCString(cInput.pubY)
+	freeCString(cInput.curve)
 	FreeProofResult(result2)
 
+	// Test 6: A panic is reported with its stack trace instead of aborting,
+	// shown with a deliberate panic under the recovery of the exports
+	fmt.Println("\n=== Test 6: Panic recovery ===")
+	result6 := func() (result C.ProofResult) {
+		defer recoverResult(&result)
+		panic("deliberate panic of the demo")
+	}()
+	if result6.error_code == C.ECDSA_ERR_PANIC {
+		trace := EcdsaLastPanicTrace()
+		fmt.Printf("✓ The panic was reported: %s (stack trace: %d bytes)\n", cStringToGoString(result6.error_msg), len(cStringToGoString(trace)))
+		EcdsaFreeString(trace)
+	} else {
+		fmt.Printf("✗ The panic returned error code %d\n", result6.error_code)
+	}
+	FreeProofResult(result6)
+
+	// Test 7: The metrics recorded by the tests above
+	fmt.Println("\n=== Test 7: EcdsaMetrics ===")
+	var metrics *C.char
+	if code := EcdsaMetrics(&metrics); code == C.ECDSA_OK {
+		for _, line := range strings.Split(cStringToGoString(metrics), "\n") {
+			if strings.HasPrefix(line, "zkecdsa_prove_duration_seconds_count") || strings.HasPrefix(line, "zkecdsa_failures_total") {
+				fmt.Println(line)
+			}
+		}
+		fmt.Println("✓ EcdsaMetrics succeeded")
+		EcdsaFreeString(metrics)
+	} else {
+		fmt.Printf("✗ EcdsaMetrics returned error code %d\n", code)
+	}
+
 	fmt.Println("\ncGO ECDSA Proof Verifier tests completed.")
-}
\ No newline at end of file
+}
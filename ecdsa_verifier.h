@@ -1,35 +1,248 @@
 #ifndef CGO_ECDSA_VERIFIER_H
 #define CGO_ECDSA_VERIFIER_H
 
+#include <stddef.h>
+#include <stdint.h>
+
 #ifdef __cplusplus
 extern "C" {
 #endif
 
+// Error codes of ProofResult. The ECDSA_ERR_INVALID_* codes and
+// ECDSA_ERR_HIGH_S report a witness input rejected before proving,
+// ECDSA_ERR_SIGNATURE_INVALID a well-formed one the circuit rejects; the
+// caller should fix the input rather than retry. Failures without a code of
+// their own are ECDSA_ERR_INTERNAL. Setting ECDSA_REQUIRE_LOW_S=1 in the
+// environment makes the proving functions reject signatures with S above n/2.
+typedef enum {
+    ECDSA_OK = 0,                       // Success
+    ECDSA_ERR_INTERNAL = 1,             // Any other failure, e.g. artifacts for another circuit
+    ECDSA_ERR_INVALID_INPUT = 2,        // Other invalid input, e.g. a wrong curve or a malformed proof
+    ECDSA_ERR_INVALID_HEX = 3,          // A field is not valid hex
+    ECDSA_ERR_INVALID_LENGTH = 4,       // A field has the wrong length
+    ECDSA_ERR_INVALID_POINT = 5,        // The public key is not on the curve
+    ECDSA_ERR_INVALID_SCALAR = 6,       // Signature R or S is not in [1, n-1]
+    ECDSA_ERR_HIGH_S = 7,               // Signature S is above n/2 under ECDSA_REQUIRE_LOW_S
+    ECDSA_ERR_ARTIFACT_MISSING = 8,     // An artifact file, e.g. the proving key, does not exist
+    ECDSA_ERR_ARTIFACT_CORRUPT = 9,     // An artifact file or verifying key does not decode
+    ECDSA_ERR_SIGNATURE_INVALID = 10,   // The signature, key path or threshold does not hold in the circuit
+    ECDSA_ERR_PROVING_FAILED = 11,      // The prover failed on a valid input
+    ECDSA_ERR_VERIFICATION_FAILED = 12, // The proof does not verify
+    ECDSA_ERR_PANIC = 13,               // A panic of the library, see EcdsaLastPanicTrace
+} EcdsaErrorCode;
+
 // Result structure for proof operations
 typedef struct {
-    char* error_msg;  // Error message (NULL if success)
-    int success;      // 1 for success, 0 for failure
+    char* error_msg;                // Error message (NULL if success)
+    int success;                    // 1 for success, 0 for failure
+    unsigned char* proof;           // Serialized Groth16 or PLONK proof (GenerateProof only, NULL otherwise)
+    size_t proof_len;               // Length of proof in bytes
+    unsigned char* public_witness;  // Serialized public witness (GenerateProof only, NULL otherwise)
+    size_t public_witness_len;      // Length of public_witness in bytes
+    EcdsaErrorCode error_code;      // ECDSA_OK if success
 } ProofResult;
 
-// Input structure for proof verification
+// Input structure for proof verification. The signature is given either by
+// r and s or by signature, the public key either by pubX and pubY or by
+// publicKey; the unused fields must be NULL.
 typedef struct {
     char* msgHash;    // Hex string of the message hash
     char* r;          // Hex string of signature R
     char* s;          // Hex string of signature S
     char* pubX;       // Hex string of public key X coordinate
     char* pubY;       // Hex string of public key Y coordinate
+    char* curve;      // "p256" or "secp256k1" (NULL: the ArtifactPaths curve, else p256)
+    char* signature;  // Hex string of the ASN.1 DER signature
+    char* publicKey;  // SEC1 point in hex (compressed or uncompressed), PKIX PEM or JWK
 } ProveInput;
 
+// Artifact locations. A NULL field falls back to its environment variable
+// (ECDSA_R1CS, ECDSA_PROVING_KEY, ECDSA_VERIFYING_KEY, ECDSA_WITNESS_INPUT,
+// ECDSA_MANIFEST),
+// or else to its default file name inside dir/<curve> (dir/batch-<size>/<curve>
+// for batches, dir/<circuit>/<curve> for the other circuits). A NULL dir falls
+// back to ECDSA_ARTIFACT_DIR, or else to the working directory.
+typedef struct {
+    char* dir;            // Artifact directory, holding one subdirectory per circuit and curve
+    char* curve;          // "p256" or "secp256k1" (NULL: p256)
+    char* r1cs;           // Path to the compiled circuit (default "r1cs.bin")
+    char* proving_key;    // Path to the proving key (default "proving_key.bin")
+    char* verifying_key;  // Path to the verifying key (default "verifying_key.bin")
+    char* witness_input;  // Path to the sample input (default "witness_input.json")
+    char* manifest;       // Path to the manifest recording the backend (default "manifest.json")
+    char* circuit;        // "ecdsa", "message", "webauthn", "batch" or "membership" (NULL: ecdsa). Only read by
+                          // EcdsaProverNew, the other functions imply the circuit
+    size_t batch_size;    // Signatures per batch proof (0: 8). Only read by EcdsaProverNew,
+                          // the batch functions take it from their input count
+} ArtifactPaths;
+
+// WebAuthn assertion, as returned by navigator.credentials.get() once the
+// base64url fields are decoded
+typedef struct {
+    unsigned char* authenticator_data;    // At most 64 bytes
+    size_t authenticator_data_len;
+    unsigned char* client_data_json;      // At most 320 bytes
+    size_t client_data_json_len;
+    unsigned char* signature;             // ASN.1 DER signature
+    size_t signature_len;
+    unsigned char* challenge;             // Expected 32-byte challenge (NULL: taken from client_data_json)
+    size_t challenge_len;
+    char* pubX;                           // Hex string of credential public key X coordinate
+    char* pubY;                           // Hex string of credential public key Y coordinate
+    char* curve;                          // "p256" or "secp256k1" (NULL: the ArtifactPaths curve, else p256)
+} WebAuthnAssertion;
+
+// Signature by one of the keys of a key tree, with the Merkle path of the
+// signing key (see cmd/key_tree)
+typedef struct {
+    ProveInput input;     // Signature, message hash and signing key
+    char* root;           // Hex string of the key tree root
+    char** path;          // Hex strings of the path_len siblings, from the leaf up
+    size_t path_len;      // Depth of the key tree
+    uint64_t index;       // Leaf index of the signing key
+} MembershipProveInput;
+
+// Opaque handle to a prover that keeps the circuit and proving key in memory.
+// 0 is never a valid handle.
+typedef uintptr_t EcdsaProverHandle;
+
 // Function declarations
+// Set the artifact locations used by RunProofVerification,
+// RunProofVerificationWithInputs and GenerateProof. The strings are copied.
+void SetArtifactPaths(ArtifactPaths paths);
+
 // Run proof verification using files (r1cs.bin, proving_key.bin, verifying_key.bin, witness_input.json)
 ProofResult RunProofVerification();
 
 // Run proof verification with custom inputs
 ProofResult RunProofVerificationWithInputs(ProveInput input);
 
-// Free memory allocated for ProofResult
+// Generate a proof only, using the configured r1cs.bin and proving_key.bin
+// of the input curve.
+// On success, proof and public_witness hold buffers released by FreeProofResult.
+ProofResult GenerateProof(ProveInput input);
+
+// Generate a WebAuthn proof only, using the configured r1cs.bin and
+// proving_key.bin of the webauthn circuit for the assertion curve. The proof
+// discloses the challenge and the public key, not the assertion.
+ProofResult GenerateWebAuthnProof(WebAuthnAssertion assertion);
+
+// Generate a single proof for count signatures, using the configured
+// r1cs.bin and proving_key.bin of the batch circuit for count and the curve
+// of inputs[0]. Every input must be on that curve. The proof discloses only a
+// commitment to the message hashes and public keys of the batch.
+ProofResult GenerateBatchProof(const ProveInput* inputs, size_t count);
+
+// Generate a membership proof only, using the configured r1cs.bin and
+// proving_key.bin of the membership circuit for the input curve. The proof
+// discloses the message hash and the key tree root, not the signing key.
+ProofResult GenerateMembershipProof(MembershipProveInput input);
+
+// Verify a proof only, against a serialized public witness and verifying key
+// (e.g. the content of verifying_key.bin). The backend, Groth16 or PLONK, is
+// detected from the verifying key. No artifact is read from disk.
+ProofResult VerifyProof(const unsigned char* proof, size_t proof_len,
+                        const unsigned char* public_witness, size_t public_witness_len,
+                        const unsigned char* vk, size_t vk_len);
+
+// Verify a proof only, rebuilding the public witness from the msgHash and
+// public key fields of input (r, s and signature are ignored). Requires artifacts generated
+// with the public-input circuit variant.
+ProofResult VerifyProofWithInputs(const unsigned char* proof, size_t proof_len,
+                                  ProveInput input,
+                                  const unsigned char* vk, size_t vk_len);
+
+// Verify a WebAuthn proof only, rebuilding the public witness from the
+// challenge, pubX and pubY fields of assertion (the challenge is taken from
+// client_data_json if NULL, the other fields are ignored).
+ProofResult VerifyWebAuthnProof(const unsigned char* proof, size_t proof_len,
+                                WebAuthnAssertion assertion,
+                                const unsigned char* vk, size_t vk_len);
+
+// Verify a batch proof only, rebuilding the commitment from the msgHash, pubX
+// and pubY fields of the count inputs, in the order they were proven (r and s
+// are ignored). The commitment hash is read from the manifest of the batch
+// artifacts.
+ProofResult VerifyBatchProofWithInputs(const unsigned char* proof, size_t proof_len,
+                                       const ProveInput* inputs, size_t count,
+                                       const unsigned char* vk, size_t vk_len);
+
+// Verify a membership proof only, rebuilding the public witness from
+// input.input.msgHash, root and path_len (path may be NULL, the signature and
+// key are ignored).
+ProofResult VerifyMembershipProof(const unsigned char* proof, size_t proof_len,
+                                  MembershipProveInput input,
+                                  const unsigned char* vk, size_t vk_len);
+
+// Load the circuit and proving key once and return a handle to them, or 0 on
+// failure. If status is not NULL it receives the outcome and must be released
+// with FreeProofResult.
+EcdsaProverHandle EcdsaProverNew(ArtifactPaths paths, ProofResult* status);
+
+// Generate a proof with a loaded prover, like GenerateProof but without
+// reading any file. input.curve must be NULL or the curve of the handle.
+// Safe to call from multiple threads on the same handle.
+ProofResult EcdsaProve(EcdsaProverHandle handle, ProveInput input);
+
+// Generate a WebAuthn proof with a prover loaded for the webauthn circuit,
+// like GenerateWebAuthnProof but without reading any file.
+ProofResult EcdsaProveWebAuthn(EcdsaProverHandle handle, WebAuthnAssertion assertion);
+
+// Generate a batch proof with a prover loaded for the batch circuit, like
+// GenerateBatchProof but without reading any file. count must be the batch
+// size of the handle.
+ProofResult EcdsaProveBatch(EcdsaProverHandle handle, const ProveInput* inputs, size_t count);
+
+// Generate a membership proof with a prover loaded for the membership
+// circuit, like GenerateMembershipProof but without reading any file.
+ProofResult EcdsaProveMembership(EcdsaProverHandle handle, MembershipProveInput input);
+
+// Release a prover. The functions taking the handle fail with
+// ECDSA_ERR_INVALID_INPUT afterwards, as they do for 0 or any unknown handle.
+void EcdsaProverFree(EcdsaProverHandle handle);
+
+// Free memory allocated for ProofResult (error message, proof and public witness)
 void FreeProofResult(ProofResult result);
 
+// Return the stack trace of the last panic recovered by any function of this
+// library, or NULL if none was, to be released with EcdsaFreeString. Every
+// function reports a panic raised on its calling thread as ECDSA_ERR_PANIC
+// instead of aborting; a panic in a background worker of the prover, or the
+// Go runtime running out of memory, still aborts the process.
+char* EcdsaLastPanicTrace(void);
+
+// Free a string returned by this library
+void EcdsaFreeString(char* str);
+
+// Log levels of EcdsaLogCallback, those of Go's log/slog
+typedef enum {
+    ECDSA_LOG_DEBUG = -4,  // Each proof and verification, and the progress of gnark
+    ECDSA_LOG_INFO = 0,    // Artifact loads, and failures caused by the input or proof
+    ECDSA_LOG_WARN = 4,    // Other failures
+    ECDSA_LOG_ERROR = 8,
+} EcdsaLogLevel;
+
+// Receives a log record: its message and its attributes as a JSON object,
+// e.g. {"circuit":"ecdsa","duration":1204000000,"kind":"invalid_input"}. Both
+// strings are only valid during the call. Records are passed one at a time
+// but from any thread.
+typedef void (*EcdsaLogCallback)(EcdsaLogLevel level, const char* message, const char* attrs_json, void* user_data);
+
+// Route the logs of the library from min_level up to callback, called with
+// user_data. A NULL callback discards them, which is the default.
+void EcdsaSetLogCallback(EcdsaLogCallback callback, EcdsaLogLevel min_level, void* user_data);
+
+// Start recording the metrics of the library: prove and verify latency
+// histograms, artifact load times, failures by error kind, proofs in flight
+// and peak memory, next to those of the Go runtime. Calling it again does
+// nothing. The message of a failure goes to the log callback.
+EcdsaErrorCode EcdsaEnableMetrics(void);
+
+// Set *metrics to the metrics in the Prometheus text format, to be released
+// with EcdsaFreeString, or to NULL on failure: ECDSA_ERR_INVALID_INPUT before
+// EcdsaEnableMetrics, ECDSA_ERR_INTERNAL if they cannot be gathered.
+EcdsaErrorCode EcdsaMetrics(char** metrics);
+
 #ifdef __cplusplus
 }
 #endif
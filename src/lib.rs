@@ -1,6 +1,6 @@
 // src/lib.rs
 use std::ffi::{CStr, CString};
-use std::os::raw::{c_char, c_int};
+use std::os::raw::{c_char, c_int, c_uchar, c_void};
 use serde::{Deserialize, Serialize};
 
 // FFI declarations matching your C interface
@@ -11,97 +11,279 @@ pub struct ProveInput {
     pub s: *const c_char,
     pub pub_x: *const c_char,
     pub pub_y: *const c_char,
+    pub curve: *const c_char,
+    pub signature: *const c_char,
+    pub public_key: *const c_char,
 }
 
+// Field order must match ProofResult in ecdsa_verifier.h
 #[repr(C)]
 pub struct ProofResult {
-    pub success: c_int,
     pub error_msg: *const c_char,
-    pub proof_data: *const c_char,
+    pub success: c_int,
+    pub proof: *const c_uchar,
+    pub proof_len: usize,
+    pub public_witness: *const c_uchar,
+    pub public_witness_len: usize,
+    pub error_code: c_int,
+}
+
+// Null fields fall back to the ECDSA_* environment variables, then to the
+// default file names inside dir
+#[repr(C)]
+pub struct ArtifactPaths {
+    pub dir: *const c_char,
+    pub curve: *const c_char,
+    pub r1cs: *const c_char,
+    pub proving_key: *const c_char,
+    pub verifying_key: *const c_char,
+    pub witness_input: *const c_char,
+    pub manifest: *const c_char,
+    pub circuit: *const c_char,
+    pub batch_size: usize,
+}
+
+// Field order must match WebAuthnAssertion in ecdsa_verifier.h
+#[repr(C)]
+pub struct WebAuthnAssertion {
+    pub authenticator_data: *const c_uchar,
+    pub authenticator_data_len: usize,
+    pub client_data_json: *const c_uchar,
+    pub client_data_json_len: usize,
+    pub signature: *const c_uchar,
+    pub signature_len: usize,
+    pub challenge: *const c_uchar,
+    pub challenge_len: usize,
+    pub pub_x: *const c_char,
+    pub pub_y: *const c_char,
+    pub curve: *const c_char,
 }
 
+// Field order must match MembershipProveInput in ecdsa_verifier.h
+#[repr(C)]
+pub struct MembershipProveInput {
+    pub input: ProveInput,
+    pub root: *const c_char,
+    pub path: *const *const c_char,
+    pub path_len: usize,
+    pub index: u64,
+}
+
+// Matches EcdsaLogCallback in ecdsa_verifier.h
+type LogCallback = extern "C" fn(level: c_int, message: *const c_char, attrs_json: *const c_char, user_data: *mut c_void);
+
 // External functions from your shared library
-// FIXED: Changed FreeProofResult to take a pointer
 extern "C" {
     fn RunProofVerification() -> ProofResult;
     fn RunProofVerificationWithInputs(input: ProveInput) -> ProofResult;
-   // fn FreeProofResult(result: *mut ProofResult);
+    fn GenerateProof(input: ProveInput) -> ProofResult;
+    fn GenerateWebAuthnProof(assertion: WebAuthnAssertion) -> ProofResult;
+    fn GenerateBatchProof(inputs: *const ProveInput, count: usize) -> ProofResult;
+    fn GenerateMembershipProof(input: MembershipProveInput) -> ProofResult;
+    fn VerifyProof(
+        proof: *const c_uchar,
+        proof_len: usize,
+        public_witness: *const c_uchar,
+        public_witness_len: usize,
+        vk: *const c_uchar,
+        vk_len: usize,
+    ) -> ProofResult;
+    fn EcdsaProverNew(paths: ArtifactPaths, status: *mut ProofResult) -> usize;
+    fn EcdsaProve(handle: usize, input: ProveInput) -> ProofResult;
+    fn EcdsaProveWebAuthn(handle: usize, assertion: WebAuthnAssertion) -> ProofResult;
+    fn EcdsaProveBatch(handle: usize, inputs: *const ProveInput, count: usize) -> ProofResult;
+    fn EcdsaProveMembership(handle: usize, input: MembershipProveInput) -> ProofResult;
+    fn EcdsaProverFree(handle: usize);
+    fn EcdsaLastPanicTrace() -> *mut c_char;
+    fn EcdsaFreeString(str: *mut c_char);
+    fn EcdsaSetLogCallback(callback: Option<LogCallback>, min_level: c_int, user_data: *mut c_void);
+    fn EcdsaEnableMetrics() -> c_int;
+    fn EcdsaMetrics(metrics: *mut *mut c_char) -> c_int;
+    fn FreeProofResult(result: ProofResult);
 }
 
 // Rust-friendly structs
 #[derive(Debug, Serialize, Deserialize, Clone)]
 pub struct EcdsaInput {
     pub msg_hash: String,
+    // Either r and s, or signature; the unused form is left empty or None
+    #[serde(default)]
     pub r: String,
+    #[serde(default)]
     pub s: String,
+    // Either pub_x and pub_y, or public_key
+    #[serde(default)]
     pub pub_x: String,
+    #[serde(default)]
     pub pub_y: String,
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub curve: Option<String>, // "p256" (default) or "secp256k1"
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub signature: Option<String>, // hex ASN.1 DER signature
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub public_key: Option<String>, // hex SEC1 point, PKIX PEM or JWK
 }
 
-#[derive(Debug, Serialize, Deserialize)]
-pub struct EcdsaProofOutput {
-    pub success: bool,
-    pub error_message: Option<String>,
-    pub proof_data: Option<String>,
+// Error codes of ProofResult, matching EcdsaErrorCode in ecdsa_verifier.h
+pub const ECDSA_OK: c_int = 0;
+pub const ECDSA_ERR_INTERNAL: c_int = 1;
+pub const ECDSA_ERR_INVALID_INPUT: c_int = 2;
+pub const ECDSA_ERR_INVALID_HEX: c_int = 3;
+pub const ECDSA_ERR_INVALID_LENGTH: c_int = 4;
+pub const ECDSA_ERR_INVALID_POINT: c_int = 5;
+pub const ECDSA_ERR_INVALID_SCALAR: c_int = 6;
+pub const ECDSA_ERR_HIGH_S: c_int = 7;
+pub const ECDSA_ERR_ARTIFACT_MISSING: c_int = 8;
+pub const ECDSA_ERR_ARTIFACT_CORRUPT: c_int = 9;
+pub const ECDSA_ERR_SIGNATURE_INVALID: c_int = 10;
+pub const ECDSA_ERR_PROVING_FAILED: c_int = 11;
+pub const ECDSA_ERR_VERIFICATION_FAILED: c_int = 12;
+pub const ECDSA_ERR_PANIC: c_int = 13;
+
+// Error of the safe wrappers, one variant per error code, holding the error
+// message of the Go side. Inputs the wrappers reject themselves, such as
+// strings with null bytes, are InvalidInput.
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum EcdsaError {
+    Internal(String), // Also any code unknown to this wrapper
+    InvalidInput(String),
+    InvalidHex(String),
+    InvalidLength(String),
+    InvalidPoint(String),
+    InvalidScalar(String),
+    HighS(String),
+    ArtifactMissing(String),
+    ArtifactCorrupt(String),
+    SignatureInvalid(String),
+    ProvingFailed(String),
+    VerificationFailed(String),
+    Panic(String),
 }
 
-// Safe Rust wrapper for file-based verification
-pub fn run_proof_verification_from_files() -> Result<EcdsaProofOutput, String> {
-    // Validate that required files exist before calling C function
-    let required_files = ["r1cs.bin", "proving_key.bin", "verifying_key.bin", "witness_input.json"];
-    for file in &required_files {
-        if !std::path::Path::new(file).exists() {
-            return Err(format!("Required file not found: {}", file));
+impl EcdsaError {
+    fn from_code(code: c_int, message: String) -> Self {
+        match code {
+            ECDSA_ERR_INVALID_INPUT => EcdsaError::InvalidInput(message),
+            ECDSA_ERR_INVALID_HEX => EcdsaError::InvalidHex(message),
+            ECDSA_ERR_INVALID_LENGTH => EcdsaError::InvalidLength(message),
+            ECDSA_ERR_INVALID_POINT => EcdsaError::InvalidPoint(message),
+            ECDSA_ERR_INVALID_SCALAR => EcdsaError::InvalidScalar(message),
+            ECDSA_ERR_HIGH_S => EcdsaError::HighS(message),
+            ECDSA_ERR_ARTIFACT_MISSING => EcdsaError::ArtifactMissing(message),
+            ECDSA_ERR_ARTIFACT_CORRUPT => EcdsaError::ArtifactCorrupt(message),
+            ECDSA_ERR_SIGNATURE_INVALID => EcdsaError::SignatureInvalid(message),
+            ECDSA_ERR_PROVING_FAILED => EcdsaError::ProvingFailed(message),
+            ECDSA_ERR_VERIFICATION_FAILED => EcdsaError::VerificationFailed(message),
+            ECDSA_ERR_PANIC => EcdsaError::Panic(message),
+            _ => EcdsaError::Internal(message),
         }
     }
 
-    // Call the C function
-    let mut result = unsafe { RunProofVerification() };
+    // Error code of the error, as in ProofResult
+    pub fn code(&self) -> c_int {
+        match self {
+            EcdsaError::Internal(_) => ECDSA_ERR_INTERNAL,
+            EcdsaError::InvalidInput(_) => ECDSA_ERR_INVALID_INPUT,
+            EcdsaError::InvalidHex(_) => ECDSA_ERR_INVALID_HEX,
+            EcdsaError::InvalidLength(_) => ECDSA_ERR_INVALID_LENGTH,
+            EcdsaError::InvalidPoint(_) => ECDSA_ERR_INVALID_POINT,
+            EcdsaError::InvalidScalar(_) => ECDSA_ERR_INVALID_SCALAR,
+            EcdsaError::HighS(_) => ECDSA_ERR_HIGH_S,
+            EcdsaError::ArtifactMissing(_) => ECDSA_ERR_ARTIFACT_MISSING,
+            EcdsaError::ArtifactCorrupt(_) => ECDSA_ERR_ARTIFACT_CORRUPT,
+            EcdsaError::SignatureInvalid(_) => ECDSA_ERR_SIGNATURE_INVALID,
+            EcdsaError::ProvingFailed(_) => ECDSA_ERR_PROVING_FAILED,
+            EcdsaError::VerificationFailed(_) => ECDSA_ERR_VERIFICATION_FAILED,
+            EcdsaError::Panic(_) => ECDSA_ERR_PANIC,
+        }
+    }
 
-    // Check for null pointers before processing
-    if result.success == 0 && result.error_msg.is_null() {
-        return Err("Unknown error: function returned failure but no error message".to_string());
+    pub fn message(&self) -> &str {
+        match self {
+            EcdsaError::Internal(message)
+            | EcdsaError::InvalidInput(message)
+            | EcdsaError::InvalidHex(message)
+            | EcdsaError::InvalidLength(message)
+            | EcdsaError::InvalidPoint(message)
+            | EcdsaError::InvalidScalar(message)
+            | EcdsaError::HighS(message)
+            | EcdsaError::ArtifactMissing(message)
+            | EcdsaError::ArtifactCorrupt(message)
+            | EcdsaError::SignatureInvalid(message)
+            | EcdsaError::ProvingFailed(message)
+            | EcdsaError::VerificationFailed(message)
+            | EcdsaError::Panic(message) => message,
+        }
     }
 
-    // Convert result back to Rust
-    let output = convert_proof_result_to_rust(&result);
-    
-    // FIXED: Free the C result by passing a mutable pointer
-    unsafe { 
-      //  FreeProofResult(&mut result as *mut ProofResult);
+    // Whether the input is at fault, so that retrying it is pointless
+    pub fn is_input_error(&self) -> bool {
+        matches!(
+            self,
+            EcdsaError::InvalidInput(_)
+                | EcdsaError::InvalidHex(_)
+                | EcdsaError::InvalidLength(_)
+                | EcdsaError::InvalidPoint(_)
+                | EcdsaError::InvalidScalar(_)
+                | EcdsaError::HighS(_)
+                | EcdsaError::SignatureInvalid(_)
+        )
     }
-    
-    Ok(output)
+}
+
+impl std::fmt::Display for EcdsaError {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        f.write_str(self.message())
+    }
+}
+
+impl std::error::Error for EcdsaError {}
+
+#[derive(Debug, Serialize, Deserialize)]
+pub struct EcdsaProofOutput {
+    pub success: bool,
+    pub error_message: Option<String>,
+    pub proof_data: Option<String>,     // Hex encoded proof (generate_proof only)
+    pub public_witness: Option<String>, // Hex encoded public witness (generate_proof only)
+}
+
+// Safe Rust wrapper for file-based verification
+// Artifact locations come from ECDSA_ARTIFACT_DIR and friends; a missing file
+// is reported with its path in error_message
+pub fn run_proof_verification_from_files() -> Result<EcdsaProofOutput, EcdsaError> {
+    // Call the C function
+    let result = unsafe { RunProofVerification() };
+
+    // Convert result back to Rust, freeing the C result
+    convert_proof_result_to_rust(result)
 }
 
 // Safe Rust wrapper for custom input verification
-pub fn run_proof_verification_with_inputs(input: EcdsaInput) -> Result<EcdsaProofOutput, String> {
+pub fn run_proof_verification_with_inputs(input: EcdsaInput) -> Result<EcdsaProofOutput, EcdsaError> {
     // Validate input strings don't contain null bytes
     if input.msg_hash.contains('\0') || input.r.contains('\0') || input.s.contains('\0') ||
        input.pub_x.contains('\0') || input.pub_y.contains('\0') {
-        return Err("Input strings cannot contain null bytes".to_string());
-    }
-
-    // Validate that required files exist before calling C function
-    let required_files = ["r1cs.bin", "proving_key.bin", "verifying_key.bin"];
-    for file in &required_files {
-        if !std::path::Path::new(file).exists() {
-            return Err(format!("Required file not found: {}", file));
-        }
+        return Err(EcdsaError::InvalidInput("Input strings cannot contain null bytes".to_string()));
     }
 
     // Convert Rust strings to C strings. These CStrings must live
     // long enough for the C function call.
     let msg_hash_c = CString::new(input.msg_hash)
-        .map_err(|e| format!("Invalid msg_hash: {}", e))?;
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid msg_hash: {}", e)))?;
     let r_c = CString::new(input.r)
-        .map_err(|e| format!("Invalid r: {}", e))?;
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid r: {}", e)))?;
     let s_c = CString::new(input.s)
-        .map_err(|e| format!("Invalid s: {}", e))?;
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid s: {}", e)))?;
     let pub_x_c = CString::new(input.pub_x)
-        .map_err(|e| format!("Invalid pub_x: {}", e))?;
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_x: {}", e)))?;
     let pub_y_c = CString::new(input.pub_y)
-        .map_err(|e| format!("Invalid pub_y: {}", e))?;
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_y: {}", e)))?;
+    let curve_c = input.curve.map(CString::new).transpose()
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid curve: {}", e)))?;
+    let signature_c = input.signature.map(CString::new).transpose()
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid signature: {}", e)))?;
+    let public_key_c = input.public_key.map(CString::new).transpose()
+        .map_err(|e| EcdsaError::InvalidInput(format!("Invalid public_key: {}", e)))?;
 
     // Create C struct using pointers to the CStrings' internal buffers
     let c_input = ProveInput {
@@ -110,56 +292,456 @@ pub fn run_proof_verification_with_inputs(input: EcdsaInput) -> Result<EcdsaProo
         s: s_c.as_ptr(),
         pub_x: pub_x_c.as_ptr(),
         pub_y: pub_y_c.as_ptr(),
+        curve: curve_c.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
+        signature: signature_c.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
+        public_key: public_key_c.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
     };
 
     // Call the C function
-    let mut result = unsafe { RunProofVerificationWithInputs(c_input) };
-
-    // Check for null pointers before processing
-    if result.success == 0 && result.error_msg.is_null() {
-        return Err("Unknown error: function returned failure but no error message".to_string());
-    }
-
-    // Convert result back to Rust
-    let output = convert_proof_result_to_rust(&result);
-    
-    // TO BE FIXED: Free the C result by passing a mutable pointer
-    unsafe { 
-       // FreeProofResult(&mut result as *mut ProofResult);
-    }
-    
-    Ok(output)
-}
-
-// Helper function to convert C ProofResult to Rust
-fn convert_proof_result_to_rust(result: &ProofResult) -> EcdsaProofOutput {
-    let success = result.success != 0;
-    
-    let error_message = if result.error_msg.is_null() {
-        None
-    } else {
-        unsafe {
-            Some(CStr::from_ptr(result.error_msg)
-                .to_string_lossy()
-                .into_owned())
+    let result = unsafe { RunProofVerificationWithInputs(c_input) };
+
+    // Convert result back to Rust, freeing the C result
+    convert_proof_result_to_rust(result)
+}
+
+// Safe Rust wrapper for proof generation only; the proof and public witness
+// are returned hex encoded so they can be shipped to a verifier
+pub fn generate_proof(input: EcdsaInput) -> Result<EcdsaProofOutput, EcdsaError> {
+    let c_strings = CInputStrings::new(input)?;
+    let result = unsafe { GenerateProof(c_strings.as_prove_input()) };
+    convert_proof_result_to_rust(result)
+}
+
+// Safe Rust wrapper for batch proof generation from the configured artifacts
+// of the batch circuit for inputs.len() signatures
+pub fn generate_batch_proof(inputs: &[EcdsaInput]) -> Result<EcdsaProofOutput, EcdsaError> {
+    let c_strings = inputs
+        .iter()
+        .map(|input| CInputStrings::new(input.clone()))
+        .collect::<Result<Vec<_>, _>>()?;
+    let c_inputs: Vec<ProveInput> = c_strings.iter().map(CInputStrings::as_prove_input).collect();
+    let result = unsafe { GenerateBatchProof(c_inputs.as_ptr(), c_inputs.len()) };
+    convert_proof_result_to_rust(result)
+}
+
+// Owns the C strings backing a ProveInput for the duration of a call
+struct CInputStrings {
+    msg_hash: CString,
+    r: CString,
+    s: CString,
+    pub_x: CString,
+    pub_y: CString,
+    curve: Option<CString>,
+    signature: Option<CString>,
+    public_key: Option<CString>,
+}
+
+impl CInputStrings {
+    fn new(input: EcdsaInput) -> Result<Self, EcdsaError> {
+        Ok(CInputStrings {
+            msg_hash: CString::new(input.msg_hash)
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid msg_hash: {}", e)))?,
+            r: CString::new(input.r)
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid r: {}", e)))?,
+            s: CString::new(input.s)
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid s: {}", e)))?,
+            pub_x: CString::new(input.pub_x)
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_x: {}", e)))?,
+            pub_y: CString::new(input.pub_y)
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_y: {}", e)))?,
+            curve: input.curve.map(CString::new).transpose()
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid curve: {}", e)))?,
+            signature: input.signature.map(CString::new).transpose()
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid signature: {}", e)))?,
+            public_key: input.public_key.map(CString::new).transpose()
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid public_key: {}", e)))?,
+        })
+    }
+
+    fn as_prove_input(&self) -> ProveInput {
+        ProveInput {
+            msg_hash: self.msg_hash.as_ptr(),
+            r: self.r.as_ptr(),
+            s: self.s.as_ptr(),
+            pub_x: self.pub_x.as_ptr(),
+            pub_y: self.pub_y.as_ptr(),
+            curve: self.curve.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
+            signature: self.signature.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
+            public_key: self.public_key.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
         }
-    };
+    }
+}
+
+// Safe Rust wrapper around a prover handle: the circuit and proving key are
+// loaded once and released when the EcdsaProver is dropped
+pub struct EcdsaProver {
+    handle: usize,
+}
+
+// The Go side guarantees the handle can be used from several threads at once
+unsafe impl Send for EcdsaProver {}
+unsafe impl Sync for EcdsaProver {}
+
+impl EcdsaProver {
+    // None paths fall back to the environment, then to r1cs.bin and
+    // proving_key.bin inside dir/<curve>; a None curve selects p256
+    pub fn new(dir: Option<&str>, curve: Option<&str>, r1cs: Option<&str>, proving_key: Option<&str>) -> Result<Self, EcdsaError> {
+        Self::new_for_circuit(None, dir, curve, r1cs, proving_key)
+    }
+
+    // Same as new for the artifacts of circuit, "ecdsa", "message" or
+    // "webauthn"; a None circuit selects ecdsa
+    pub fn new_for_circuit(
+        circuit: Option<&str>,
+        dir: Option<&str>,
+        curve: Option<&str>,
+        r1cs: Option<&str>,
+        proving_key: Option<&str>,
+    ) -> Result<Self, EcdsaError> {
+        Self::new_with_paths(circuit, 0, dir, curve, r1cs, proving_key)
+    }
+
+    // Same as new for the artifacts of the batch circuit for batch_size
+    // signatures, inside dir/batch-<batch_size>/<curve>
+    pub fn new_batch(
+        batch_size: usize,
+        dir: Option<&str>,
+        curve: Option<&str>,
+        r1cs: Option<&str>,
+        proving_key: Option<&str>,
+    ) -> Result<Self, EcdsaError> {
+        Self::new_with_paths(Some("batch"), batch_size, dir, curve, r1cs, proving_key)
+    }
+
+    fn new_with_paths(
+        circuit: Option<&str>,
+        batch_size: usize,
+        dir: Option<&str>,
+        curve: Option<&str>,
+        r1cs: Option<&str>,
+        proving_key: Option<&str>,
+    ) -> Result<Self, EcdsaError> {
+        let circuit_c = circuit
+            .map(CString::new)
+            .transpose()
+            .map_err(|e| EcdsaError::InvalidInput(format!("Invalid circuit: {}", e)))?;
+        let dir_c = dir
+            .map(CString::new)
+            .transpose()
+            .map_err(|e| EcdsaError::InvalidInput(format!("Invalid dir path: {}", e)))?;
+        let curve_c = curve
+            .map(CString::new)
+            .transpose()
+            .map_err(|e| EcdsaError::InvalidInput(format!("Invalid curve: {}", e)))?;
+        let r1cs_c = r1cs
+            .map(CString::new)
+            .transpose()
+            .map_err(|e| EcdsaError::InvalidInput(format!("Invalid r1cs path: {}", e)))?;
+        let proving_key_c = proving_key
+            .map(CString::new)
+            .transpose()
+            .map_err(|e| EcdsaError::InvalidInput(format!("Invalid proving_key path: {}", e)))?;
+
+        let paths = ArtifactPaths {
+            dir: dir_c.as_ref().map_or(std::ptr::null(), |p| p.as_ptr()),
+            curve: curve_c.as_ref().map_or(std::ptr::null(), |p| p.as_ptr()),
+            r1cs: r1cs_c.as_ref().map_or(std::ptr::null(), |p| p.as_ptr()),
+            proving_key: proving_key_c.as_ref().map_or(std::ptr::null(), |p| p.as_ptr()),
+            verifying_key: std::ptr::null(),
+            witness_input: std::ptr::null(),
+            manifest: std::ptr::null(),
+            circuit: circuit_c.as_ref().map_or(std::ptr::null(), |p| p.as_ptr()),
+            batch_size,
+        };
+
+        let mut status = ProofResult {
+            error_msg: std::ptr::null(),
+            success: 0,
+            proof: std::ptr::null(),
+            proof_len: 0,
+            public_witness: std::ptr::null(),
+            public_witness_len: 0,
+            error_code: 0,
+        };
+        let handle = unsafe { EcdsaProverNew(paths, &mut status) };
+        let outcome = convert_proof_result_to_rust(status);
+        if handle == 0 {
+            outcome?;
+            return Err(EcdsaError::Internal("Unknown error".to_string()));
+        }
+        Ok(EcdsaProver { handle })
+    }
+
+    pub fn prove(&self, input: EcdsaInput) -> Result<EcdsaProofOutput, EcdsaError> {
+        let c_strings = CInputStrings::new(input)?;
+        let result = unsafe { EcdsaProve(self.handle, c_strings.as_prove_input()) };
+        convert_proof_result_to_rust(result)
+    }
+
+    // Requires a prover created with new_for_circuit(Some("webauthn"), ...)
+    pub fn prove_webauthn(&self, input: &WebAuthnInput) -> Result<EcdsaProofOutput, EcdsaError> {
+        let c_strings = CWebAuthnStrings::new(input)?;
+        let result = unsafe { EcdsaProveWebAuthn(self.handle, c_strings.as_assertion(input)) };
+        convert_proof_result_to_rust(result)
+    }
+
+    // Requires a prover created with new_for_circuit(Some("membership"), ...)
+    pub fn prove_membership(&self, input: &MembershipInput) -> Result<EcdsaProofOutput, EcdsaError> {
+        let c_strings = CMembershipStrings::new(input)?;
+        let result = unsafe { EcdsaProveMembership(self.handle, c_strings.as_membership_input(input)) };
+        convert_proof_result_to_rust(result)
+    }
+
+    // Requires a prover created with new_batch(inputs.len(), ...)
+    pub fn prove_batch(&self, inputs: &[EcdsaInput]) -> Result<EcdsaProofOutput, EcdsaError> {
+        let c_strings = inputs
+            .iter()
+            .map(|input| CInputStrings::new(input.clone()))
+            .collect::<Result<Vec<_>, _>>()?;
+        let c_inputs: Vec<ProveInput> = c_strings.iter().map(CInputStrings::as_prove_input).collect();
+        let result = unsafe { EcdsaProveBatch(self.handle, c_inputs.as_ptr(), c_inputs.len()) };
+        convert_proof_result_to_rust(result)
+    }
+}
 
-    let proof_data = if result.proof_data.is_null() {
-        None
-    } else {
-        unsafe {
-            Some(CStr::from_ptr(result.proof_data)
-                .to_string_lossy()
-                .into_owned())
+// Signature by one of the keys of a key tree, with the Merkle path of the
+// signing key
+#[derive(Debug, Serialize, Deserialize, Clone)]
+pub struct MembershipInput {
+    #[serde(flatten)]
+    pub input: EcdsaInput,
+    pub root: String,
+    pub path: Vec<String>, // Siblings from the leaf up
+    pub index: u64,
+}
+
+// Owns the C strings backing a MembershipProveInput for the duration of a call
+struct CMembershipStrings {
+    input: CInputStrings,
+    root: CString,
+    path: Vec<CString>,
+    path_ptrs: Vec<*const c_char>,
+}
+
+impl CMembershipStrings {
+    fn new(input: &MembershipInput) -> Result<Self, EcdsaError> {
+        let path = input
+            .path
+            .iter()
+            .map(|sibling| {
+                CString::new(sibling.clone())
+                    .map_err(|e| EcdsaError::InvalidInput(format!("Invalid path: {}", e)))
+            })
+            .collect::<Result<Vec<_>, _>>()?;
+        let path_ptrs = path.iter().map(|sibling| sibling.as_ptr()).collect();
+        Ok(CMembershipStrings {
+            input: CInputStrings::new(input.input.clone())?,
+            root: CString::new(input.root.clone())
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid root: {}", e)))?,
+            path,
+            path_ptrs,
+        })
+    }
+
+    fn as_membership_input(&self, input: &MembershipInput) -> MembershipProveInput {
+        MembershipProveInput {
+            input: self.input.as_prove_input(),
+            root: self.root.as_ptr(),
+            path: self.path_ptrs.as_ptr(),
+            path_len: self.path.len(),
+            index: input.index,
         }
+    }
+}
+
+// Safe Rust wrapper for membership proof generation from the configured artifacts
+pub fn generate_membership_proof(input: &MembershipInput) -> Result<EcdsaProofOutput, EcdsaError> {
+    let c_strings = CMembershipStrings::new(input)?;
+    let result = unsafe { GenerateMembershipProof(c_strings.as_membership_input(input)) };
+    convert_proof_result_to_rust(result)
+}
+
+// Raw WebAuthn assertion, with the base64url fields of the browser response
+// already decoded
+#[derive(Debug, Clone)]
+pub struct WebAuthnInput {
+    pub authenticator_data: Vec<u8>,
+    pub client_data_json: Vec<u8>,
+    pub signature: Vec<u8>,         // ASN.1 DER
+    pub challenge: Option<Vec<u8>>, // Expected challenge, taken from client_data_json if None
+    pub pub_x: String,
+    pub pub_y: String,
+    pub curve: Option<String>,
+}
+
+// Owns the C strings backing a WebAuthnAssertion for the duration of a call
+struct CWebAuthnStrings {
+    pub_x: CString,
+    pub_y: CString,
+    curve: Option<CString>,
+}
+
+impl CWebAuthnStrings {
+    fn new(input: &WebAuthnInput) -> Result<Self, EcdsaError> {
+        Ok(CWebAuthnStrings {
+            pub_x: CString::new(input.pub_x.clone())
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_x: {}", e)))?,
+            pub_y: CString::new(input.pub_y.clone())
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid pub_y: {}", e)))?,
+            curve: input.curve.clone().map(CString::new).transpose()
+                .map_err(|e| EcdsaError::InvalidInput(format!("Invalid curve: {}", e)))?,
+        })
+    }
+
+    // The byte buffers are borrowed from input, which must outlive the call
+    fn as_assertion(&self, input: &WebAuthnInput) -> WebAuthnAssertion {
+        let challenge = input.challenge.as_deref().unwrap_or(&[]);
+        WebAuthnAssertion {
+            authenticator_data: input.authenticator_data.as_ptr(),
+            authenticator_data_len: input.authenticator_data.len(),
+            client_data_json: input.client_data_json.as_ptr(),
+            client_data_json_len: input.client_data_json.len(),
+            signature: input.signature.as_ptr(),
+            signature_len: input.signature.len(),
+            challenge: if challenge.is_empty() { std::ptr::null() } else { challenge.as_ptr() },
+            challenge_len: challenge.len(),
+            pub_x: self.pub_x.as_ptr(),
+            pub_y: self.pub_y.as_ptr(),
+            curve: self.curve.as_ref().map_or(std::ptr::null(), |c| c.as_ptr()),
+        }
+    }
+}
+
+// Safe Rust wrapper for WebAuthn proof generation from the configured artifacts
+pub fn generate_webauthn_proof(input: &WebAuthnInput) -> Result<EcdsaProofOutput, EcdsaError> {
+    let c_strings = CWebAuthnStrings::new(input)?;
+    let result = unsafe { GenerateWebAuthnProof(c_strings.as_assertion(input)) };
+    convert_proof_result_to_rust(result)
+}
+
+impl Drop for EcdsaProver {
+    fn drop(&mut self) {
+        unsafe { EcdsaProverFree(self.handle) };
+    }
+}
+
+// Stack trace of the last panic the library recovered from, reported as
+// EcdsaError::Panic, for debugging
+pub fn last_panic_trace() -> Option<String> {
+    unsafe {
+        let trace = EcdsaLastPanicTrace();
+        if trace.is_null() {
+            return None;
+        }
+        let owned = CStr::from_ptr(trace).to_string_lossy().into_owned();
+        EcdsaFreeString(trace);
+        Some(owned)
+    }
+}
+
+// Log levels of the library, EcdsaLogLevel in ecdsa_verifier.h
+pub const LOG_DEBUG: i32 = -4;
+pub const LOG_INFO: i32 = 0;
+pub const LOG_WARN: i32 = 4;
+pub const LOG_ERROR: i32 = 8;
+
+// Receiver of the logs of the library: level, message and attributes as a
+// JSON object
+pub type Logger = fn(level: i32, message: &str, attrs_json: &str);
+
+extern "C" fn log_trampoline(level: c_int, message: *const c_char, attrs_json: *const c_char, user_data: *mut c_void) {
+    // user_data is the Logger passed to set_logger
+    let logger: Logger = unsafe { std::mem::transmute(user_data) };
+    let message = unsafe { CStr::from_ptr(message).to_string_lossy() };
+    let attrs_json = unsafe { CStr::from_ptr(attrs_json).to_string_lossy() };
+    // A panic must not unwind into the Go caller, which would abort the process
+    let _ = std::panic::catch_unwind(|| logger(level, &message, &attrs_json));
+}
+
+// Route the logs of the library from min_level up to logger, or discard them
+// with None, the default
+pub fn set_logger(logger: Option<Logger>, min_level: i32) {
+    unsafe {
+        match logger {
+            Some(logger) => EcdsaSetLogCallback(Some(log_trampoline), min_level, logger as *mut c_void),
+            None => EcdsaSetLogCallback(None, min_level, std::ptr::null_mut()),
+        }
+    }
+}
+
+// Start recording the metrics of the library, see metrics. The message of a
+// failure goes to the logger
+pub fn enable_metrics() -> Result<(), EcdsaError> {
+    match unsafe { EcdsaEnableMetrics() } {
+        ECDSA_OK => Ok(()),
+        code => Err(EcdsaError::from_code(code, "Enabling metrics failed".to_string())),
+    }
+}
+
+// Metrics of the library in the Prometheus text format, an invalid input
+// error before enable_metrics
+pub fn metrics() -> Result<String, EcdsaError> {
+    unsafe {
+        let mut metrics: *mut c_char = std::ptr::null_mut();
+        let code = EcdsaMetrics(&mut metrics);
+        if code != ECDSA_OK || metrics.is_null() {
+            return Err(EcdsaError::from_code(code, "Reading metrics failed".to_string()));
+        }
+        let owned = CStr::from_ptr(metrics).to_string_lossy().into_owned();
+        EcdsaFreeString(metrics);
+        Ok(owned)
+    }
+}
+
+// Safe Rust wrapper for verification only, from raw proof, public witness
+// and verifying key bytes
+pub fn verify_proof(proof: &[u8], public_witness: &[u8], vk: &[u8]) -> Result<EcdsaProofOutput, EcdsaError> {
+    let result = unsafe {
+        VerifyProof(
+            proof.as_ptr(),
+            proof.len(),
+            public_witness.as_ptr(),
+            public_witness.len(),
+            vk.as_ptr(),
+            vk.len(),
+        )
     };
+    convert_proof_result_to_rust(result)
+}
 
-    EcdsaProofOutput {
-        success,
-        error_message,
-        proof_data,
+// Helper function to hex encode a C buffer
+fn c_buffer_to_hex(ptr: *const c_uchar, len: usize) -> Option<String> {
+    if ptr.is_null() {
+        return None;
+    }
+    let bytes = unsafe { std::slice::from_raw_parts(ptr, len) };
+    Some(bytes.iter().map(|b| format!("{:02x}", b)).collect())
+}
+
+// Helper function to convert C ProofResult to Rust, a failure becoming the
+// EcdsaError of its error code. The C result is freed once copied
+fn convert_proof_result_to_rust(result: ProofResult) -> Result<EcdsaProofOutput, EcdsaError> {
+    if result.success == 0 {
+        let message = if result.error_msg.is_null() {
+            "Unknown error: function returned failure but no error message".to_string()
+        } else {
+            unsafe { CStr::from_ptr(result.error_msg).to_string_lossy().into_owned() }
+        };
+        let code = result.error_code;
+        unsafe { FreeProofResult(result) };
+        return Err(EcdsaError::from_code(code, message));
     }
+
+    let proof_data = c_buffer_to_hex(result.proof, result.proof_len);
+    let public_witness = c_buffer_to_hex(result.public_witness, result.public_witness_len);
+    unsafe { FreeProofResult(result) };
+
+    Ok(EcdsaProofOutput {
+        success: true,
+        error_message: None,
+        proof_data,
+        public_witness,
+    })
 }
 
 // Node.js bindings using Neon
@@ -171,7 +753,7 @@ mod nodejs {
     fn js_run_proof_verification_from_files(mut cx: FunctionContext) -> JsResult<JsObject> {
         match run_proof_verification_from_files() {
             Ok(result) => create_js_result(&mut cx, result),
-            Err(e) => cx.throw_error(e),
+            Err(e) => create_js_error(&mut cx, e),
         }
     }
 
@@ -216,12 +798,15 @@ mod nodejs {
             s,
             pub_x,
             pub_y,
+            curve: None,
+            signature: None,
+            public_key: None,
         };
 
         // Run verification
         match run_proof_verification_with_inputs(input) {
             Ok(result) => create_js_result(&mut cx, result),
-            Err(e) => cx.throw_error(e),
+            Err(e) => create_js_error(&mut cx, e),
         }
     }
 
@@ -238,6 +823,9 @@ mod nodejs {
             let null = cx.null();
             js_result.set(cx, "errorMessage", null)?;
         }
+
+        let error_code = cx.number(ECDSA_OK);
+        js_result.set(cx, "errorCode", error_code)?;
         
         if let Some(proof) = result.proof_data {
             let proof_str = cx.string(proof);
@@ -250,6 +838,25 @@ mod nodejs {
         Ok(js_result)
     }
 
+    // Failures are returned rather than thrown, with the code of the error
+    fn create_js_error(cx: &mut FunctionContext, error: EcdsaError) -> JsResult<JsObject> {
+        let js_result = cx.empty_object();
+
+        let success = cx.boolean(false);
+        js_result.set(cx, "success", success)?;
+
+        let error_str = cx.string(error.message());
+        js_result.set(cx, "errorMessage", error_str)?;
+
+        let error_code = cx.number(error.code());
+        js_result.set(cx, "errorCode", error_code)?;
+
+        let null = cx.null();
+        js_result.set(cx, "proofData", null)?;
+
+        Ok(js_result)
+    }
+
     #[neon::main]
     fn main(mut cx: ModuleContext) -> NeonResult<()> {
         cx.export_function("runProofVerificationFromFiles", js_run_proof_verification_from_files)?;
@@ -267,7 +874,7 @@ mod wasm {
     #[wasm_bindgen]
     pub fn run_proof_verification_from_files_wasm() -> Result<String, JsValue> {
         let result = run_proof_verification_from_files()
-            .map_err(|e| JsValue::from_str(&e))?;
+            .map_err(|e| JsValue::from_str(e.message()))?;
 
         serde_json::to_string(&result)
             .map_err(|e| JsValue::from_str(&format!("Failed to serialize result: {}", e)))
@@ -279,7 +886,7 @@ mod wasm {
             .map_err(|e| JsValue::from_str(&format!("Failed to parse input: {}", e)))?;
 
         let result = run_proof_verification_with_inputs(input)
-            .map_err(|e| JsValue::from_str(&e))?;
+            .map_err(|e| JsValue::from_str(e.message()))?;
 
         serde_json::to_string(&result)
             .map_err(|e| JsValue::from_str(&format!("Failed to serialize result: {}", e)))
@@ -349,6 +956,9 @@ mod tests {
             s: "74b885b6c97c76c5f80f7fb322f686a506802dbbc10552822cf536b9af50de59".to_string(),
             pub_x: "3e331f713dde41d6d794d9f3f51c9325d5454185152899770539cb5c3b284d8a".to_string(),
             pub_y: "f60103fe7a37cab1cf3648c60bb71cdbe47cb850a1fea3a5fc218d3075320987".to_string(),
+            curve: None,
+            signature: None,
+            public_key: None,
         };
 
         match run_proof_verification_with_inputs(input) {
@@ -375,6 +985,9 @@ mod tests {
             s: "test_s".to_string(),
             pub_x: "test_pub_x".to_string(),
             pub_y: "test_pub_y".to_string(),
+            curve: None,
+            signature: None,
+            public_key: None,
         };
 
         let json = serde_json::to_string(&input).unwrap();
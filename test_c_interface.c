@@ -1,11 +1,57 @@
+//go:build ignore
+
+#include <pthread.h>
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
 #include "ecdsa_verifier.h"
 
-int main() {
+// Read a whole file into a malloc'd buffer (caller must free)
+static unsigned char* read_file(const char* path, size_t* len) {
+    FILE* f = fopen(path, "rb");
+    if (!f) {
+        return NULL;
+    }
+    fseek(f, 0, SEEK_END);
+    long size = ftell(f);
+    fseek(f, 0, SEEK_SET);
+    unsigned char* buf = malloc(size > 0 ? size : 1);
+    if (buf && fread(buf, 1, size, f) != (size_t)size) {
+        free(buf);
+        buf = NULL;
+    }
+    fclose(f);
+    *len = buf ? (size_t)size : 0;
+    return buf;
+}
+
+// Shared state for the multi-threaded handle test
+typedef struct {
+    EcdsaProverHandle handle;
+    ProveInput input;
+    int success;
+} ProveJob;
+
+static void* prove_worker(void* arg) {
+    ProveJob* job = (ProveJob*)arg;
+    ProofResult result = EcdsaProve(job->handle, job->input);
+    job->success = result.success;
+    if (!result.success) {
+        printf("✗ EcdsaProve failed: %s\n",
+               result.error_msg ? result.error_msg : "Unknown error");
+    }
+    FreeProofResult(result);
+    return NULL;
+}
+
+int main(int argc, char** argv) {
     printf("Testing C interface to ECDSA Proof Verifier...\n");
     
+    // Optional artifact directory, otherwise ECDSA_ARTIFACT_DIR or the working directory
+    const char* dir = argc > 1 ? argv[1] : getenv("ECDSA_ARTIFACT_DIR");
+    ArtifactPaths paths = { .dir = (char*)dir };
+    SetArtifactPaths(paths);
+    
     // Test 1: Run proof verification from files
     printf("\n=== Test 1: Proof verification from files ===\n");
     ProofResult result1 = RunProofVerification();
@@ -27,7 +73,8 @@ int main() {
         .r = "d5675d2bf43d09c689c1c5f080467c40493ecfad7b8a9753ed4019615913c52b",
         .s = "9f6c5744183080ed5da9d3c1dacea9db10c07d4721dfe4aba8e217720635e3df",
         .pubX = "ec2a78c1dcde84326c812a7666a9167022ad2b388035d8fdd97b495939ce7174",
-        .pubY = "dee8b2f2861a1bee29932861deb5e045580d3bbe1592d5aa1bbbe7322f2396e9"
+        .pubY = "dee8b2f2861a1bee29932861deb5e045580d3bbe1592d5aa1bbbe7322f2396e9",
+        .curve = "p256"
     };
     
     ProofResult result2 = RunProofVerificationWithInputs(input);
@@ -40,6 +87,61 @@ int main() {
     }
     FreeProofResult(result2);
     
+    // Test 3: Prove and verify separately, exchanging only proof bytes
+    printf("\n=== Test 3: GenerateProof + VerifyProof ===\n");
+    ProofResult result3 = GenerateProof(input);
+    if (result3.success) {
+        printf("✓ GenerateProof succeeded (proof: %zu bytes, public witness: %zu bytes)\n",
+               result3.proof_len, result3.public_witness_len);
+
+        char vk_path[4096];
+        snprintf(vk_path, sizeof(vk_path), "%s/p256/verifying_key.bin", dir ? dir : ".");
+        size_t vk_len = 0;
+        unsigned char* vk = read_file(vk_path, &vk_len);
+        if (vk) {
+            ProofResult result4 = VerifyProof(result3.proof, result3.proof_len,
+                                              result3.public_witness, result3.public_witness_len,
+                                              vk, vk_len);
+            if (result4.success) {
+                printf("✓ VerifyProof succeeded!\n");
+            } else {
+                printf("✗ VerifyProof failed: %s\n",
+                       result4.error_msg ? result4.error_msg : "Unknown error");
+            }
+            FreeProofResult(result4);
+            free(vk);
+        } else {
+            printf("✗ Could not read %s\n", vk_path);
+        }
+    } else {
+        printf("✗ GenerateProof failed: %s\n",
+               result3.error_msg ? result3.error_msg : "Unknown error");
+    }
+    FreeProofResult(result3);
+    
+    // Test 4: Keep the artifacts loaded and prove from two threads at once
+    printf("\n=== Test 4: EcdsaProverNew + EcdsaProve (2 threads) ===\n");
+    ProofResult status;
+    EcdsaProverHandle prover = EcdsaProverNew(paths, &status);
+    if (prover) {
+        ProveJob jobs[2] = { { prover, input, 0 }, { prover, input, 0 } };
+        pthread_t threads[2];
+        for (int i = 0; i < 2; i++) {
+            pthread_create(&threads[i], NULL, prove_worker, &jobs[i]);
+        }
+        for (int i = 0; i < 2; i++) {
+            pthread_join(threads[i], NULL);
+        }
+        if (jobs[0].success && jobs[1].success) {
+            printf("✓ EcdsaProve succeeded from both threads!\n");
+        }
+        EcdsaProverFree(prover);
+    } else {
+        printf("✗ EcdsaProverNew failed: %s\n",
+               status.error_msg ? status.error_msg : "Unknown error");
+    }
+    FreeProofResult(status);
+    
     printf("\nC interface tests completed.\n");
     return 0;
 }
@@ -0,0 +1,625 @@
+package zkecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// Default artifact file names, relative to the namespace of the artifact
+// directory: <dir>/<curve> for ECDSA (e.g. <dir>/p256/r1cs.bin),
+// <dir>/batch-<size>/<curve> for batches and <dir>/<circuit>/<curve> for the
+// other circuits.
+const (
+	R1CSFile         = "r1cs.bin"
+	ProvingKeyFile   = "proving_key.bin"
+	VerifyingKeyFile = "verifying_key.bin"
+	WitnessInputFile = "witness_input.json"
+	ManifestFile     = "manifest.json"
+)
+
+// Environment variables consulted for paths that are not set explicitly.
+const (
+	EnvArtifactDir  = "ECDSA_ARTIFACT_DIR"
+	EnvR1CS         = "ECDSA_R1CS"
+	EnvProvingKey   = "ECDSA_PROVING_KEY"
+	EnvVerifyingKey = "ECDSA_VERIFYING_KEY"
+	EnvWitnessInput = "ECDSA_WITNESS_INPUT"
+	EnvManifest     = "ECDSA_MANIFEST"
+)
+
+// ErrArtifactMissing is returned when an artifact file does not exist.
+var ErrArtifactMissing = errors.New("artifact missing")
+
+// ErrArtifactCorrupt is returned when an artifact file or verifying key does
+// not decode, or a manifest is incomplete.
+var ErrArtifactCorrupt = errors.New("artifact corrupt")
+
+// Paths locates the artifact files. Use Resolve to fill in unset fields.
+type Paths struct {
+	Dir          string      // Directory holding one namespace per circuit and curve
+	Circuit      CircuitType // Circuit the artifacts are for, CircuitECDSA if empty
+	Curve        Curve       // Curve the artifacts are for, P256 if empty
+	BatchSize    int         // Batch circuit only: signatures per proof, DefaultBatchSize if zero
+	R1CS         string
+	ProvingKey   string
+	VerifyingKey string
+	WitnessInput string
+	Manifest     string
+}
+
+// Resolve returns a copy of p where every empty file path is taken from its
+// environment variable, or else is the default file name inside the
+// namespace of Dir. An empty Dir is taken from ECDSA_ARTIFACT_DIR, or else is
+// the working directory.
+func (p Paths) Resolve() Paths {
+	if p.Dir == "" {
+		p.Dir = os.Getenv(EnvArtifactDir)
+	}
+	p.Circuit = p.Circuit.canonical()
+	p.Curve = p.Curve.canonical()
+	resolve := func(path, env, name string) string {
+		if path != "" {
+			return path
+		}
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+		return filepath.Join(p.Namespace(), name)
+	}
+	p.R1CS = resolve(p.R1CS, EnvR1CS, R1CSFile)
+	p.ProvingKey = resolve(p.ProvingKey, EnvProvingKey, ProvingKeyFile)
+	p.VerifyingKey = resolve(p.VerifyingKey, EnvVerifyingKey, VerifyingKeyFile)
+	p.WitnessInput = resolve(p.WitnessInput, EnvWitnessInput, WitnessInputFile)
+	p.Manifest = resolve(p.Manifest, EnvManifest, ManifestFile)
+	return p
+}
+
+// Namespace returns the directory holding the default files for Circuit
+// and Curve, and BatchSize for batches.
+func (p Paths) Namespace() string {
+	switch circuit := p.Circuit.canonical(); circuit {
+	case CircuitECDSA:
+		return filepath.Join(p.Dir, string(p.Curve.canonical()))
+	case CircuitBatch:
+		return filepath.Join(p.Dir, fmt.Sprintf("%s-%d", circuit, p.batchSize()), string(p.Curve.canonical()))
+	default:
+		return filepath.Join(p.Dir, string(circuit), string(p.Curve.canonical()))
+	}
+}
+
+// batchSize returns the batch size the paths are for.
+func (p Paths) batchSize() int {
+	if p.BatchSize == 0 {
+		return DefaultBatchSize
+	}
+	return p.BatchSize
+}
+
+// readManifest reads the manifest of the resolved paths, checking it is for
+// the batch size of the paths in the case of batches.
+func (p Paths) readManifest() (*Manifest, error) {
+	m, err := ReadManifest(p.Manifest, p.Circuit, p.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if m.Circuit == CircuitBatch && m.BatchSize != p.batchSize() {
+		return nil, fmt.Errorf("artifacts at %s are for batches of %d signatures, not %d", p.Manifest, m.BatchSize, p.batchSize())
+	}
+	return m, nil
+}
+
+// DefaultPaths returns the paths for curve resolved from the environment alone.
+func DefaultPaths(curve Curve) Paths {
+	return Paths{Curve: curve}.Resolve()
+}
+
+// readArtifact reads an artifact, reporting a missing or undecodable file
+// with its role and path.
+func readArtifact(kind, filename string, data interface{}) error {
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s not found at %s", ErrArtifactMissing, kind, filename)
+	}
+	return corruptArtifact(kind, ReadFromFile(filename, data))
+}
+
+// corruptArtifact marks a decoding error of ReadFromFile as ErrArtifactCorrupt,
+// leaving the errors of the file system as they are.
+func corruptArtifact(kind string, err error) error {
+	var pathErr *fs.PathError
+	if err == nil || errors.As(err, &pathErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %w", ErrArtifactCorrupt, kind, err)
+}
+
+// Manifest records what a set of artifacts was generated for, so loaders
+// know how to decode the keys without being told, and ties the files of the
+// set together.
+type Manifest struct {
+	Circuit CircuitType `json:"circuit,omitempty"` // CircuitECDSA if empty
+	Curve   Curve       `json:"curve"`
+	Backend Backend     `json:"backend"`
+
+	MaxMessageLen  int       `json:"maxMessageLen,omitempty"`  // Message circuit only: message capacity in bytes
+	BatchSize      int       `json:"batchSize,omitempty"`      // Batch circuit only: signatures per proof
+	MerkleDepth    int       `json:"merkleDepth,omitempty"`    // Membership circuit only: depth of the key tree
+	Signers        int       `json:"signers,omitempty"`        // Threshold circuit only: number of keys n
+	Threshold      int       `json:"threshold,omitempty"`      // Threshold circuit only: signatures required k, zero if it is a public input
+	CommitmentHash FieldHash `json:"commitmentHash,omitempty"` // Message, batch, membership, nullifier and threshold circuits: hash of the commitment, key tree or nullifier, none if empty
+
+	// Integrity and compatibility records written by Save. Empty fields, as
+	// in older manifests, are not checked.
+	GnarkVersion string          `json:"gnarkVersion,omitempty"` // gnark version the artifacts were serialized with
+	Constraints  int             `json:"constraints,omitempty"`  // Constraints of the circuit
+	SHA256       ArtifactDigests `json:"sha256"`                 // Digests of the artifact files
+
+	path string // File the manifest was read from
+}
+
+// ArtifactDigests holds the hex SHA-256 digests of the artifact files.
+type ArtifactDigests struct {
+	R1CS         string `json:"r1cs,omitempty"`
+	ProvingKey   string `json:"provingKey,omitempty"`
+	VerifyingKey string `json:"verifyingKey,omitempty"`
+}
+
+// GnarkVersion returns the version of gnark built into the running binary,
+// or the empty string if it is not known.
+func GnarkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "github.com/consensys/gnark" {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if strings.HasPrefix(dep.Version, "v") {
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// majorMinor returns the vX.Y prefix of a version, the part the
+// serialization formats of gnark are stable across.
+func majorMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// checkVersion reports an error if the artifacts were serialized with a
+// release of gnark whose formats may differ from the running one.
+func (m *Manifest) checkVersion() error {
+	running := GnarkVersion()
+	if m.GnarkVersion == "" || running == "" || majorMinor(m.GnarkVersion) == majorMinor(running) {
+		return nil
+	}
+	return fmt.Errorf("%w: artifacts at %s were generated with gnark %s, this build uses %s: regenerate them", ErrArtifactCorrupt, m.path, m.GnarkVersion, running)
+}
+
+// checkFile reports an error if the SHA-256 digest of filename is not
+// digest, as recorded in the manifest for the artifact kind. An empty digest
+// is not checked.
+func (m *Manifest) checkFile(kind, filename, digest string) error {
+	if digest == "" {
+		return nil
+	}
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s not found at %s", ErrArtifactMissing, kind, filename)
+	}
+	actual, err := FileDigest(filename)
+	if err != nil {
+		return err
+	}
+	return m.checkDigest(kind, filename, actual, digest)
+}
+
+// checkDigest reports an error if actual, the digest of filename, is not
+// digest as recorded in the manifest.
+func (m *Manifest) checkDigest(kind, filename, actual, digest string) error {
+	if actual != digest {
+		return fmt.Errorf("%w: %s at %s does not match the manifest %s (SHA-256 %s, expected %s): it is stale or was modified", ErrArtifactCorrupt, kind, filename, m.path, actual, digest)
+	}
+	return nil
+}
+
+// readProvingKey reads the proving key at filename for the backend of the
+// manifest, checking its digest as the key is read rather than in a separate
+// pass over the file. A key that fails to load is still checked against the
+// digest first, so a stale key is reported as such.
+func (m *Manifest) readProvingKey(filename string) (ProvingKey, KeyEncoding, error) {
+	digest := m.SHA256.ProvingKey
+	if digest == "" {
+		return ReadProvingKey(filename, m.Backend)
+	}
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return nil, "", fmt.Errorf("%w: proving key not found at %s", ErrArtifactMissing, filename)
+	}
+	pk, err := m.Backend.newProvingKey()
+	if err != nil {
+		return nil, "", err
+	}
+	h := sha256.New()
+	encoding, err := readProvingKey(filename, pk, h)
+	if err != nil {
+		if err := m.checkFile("proving key", filename, digest); err != nil {
+			return nil, "", err
+		}
+		return nil, "", corruptArtifact("proving key", err)
+	}
+	if err := m.checkDigest("proving key", filename, hex.EncodeToString(h.Sum(nil)), digest); err != nil {
+		return nil, "", err
+	}
+	return pk, encoding, nil
+}
+
+// checkConstraints reports an error if ccs does not have the number of
+// constraints recorded in the manifest, if any.
+func (m *Manifest) checkConstraints(ccs constraint.ConstraintSystem) error {
+	if m.Constraints != 0 && ccs.GetNbConstraints() != m.Constraints {
+		return fmt.Errorf("%w: constraint system has %d constraints, the manifest %s records %d", ErrArtifactCorrupt, ccs.GetNbConstraints(), m.path, m.Constraints)
+	}
+	return nil
+}
+
+// FileDigest returns the hex SHA-256 digest of the content of filename.
+func FileDigest(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadManifest reads the manifest at filename and checks it describes
+// artifacts for circuit and curve, serialized with a compatible release of
+// gnark. A missing manifest is ErrArtifactMissing, since the artifact files
+// could not be checked against their digests without it.
+func ReadManifest(filename string, circuit CircuitType, curve Curve) (*Manifest, error) {
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: manifest not found at %s: regenerate the artifacts", ErrArtifactMissing, filename)
+	}
+	m := Manifest{path: filename}
+	if err := corruptArtifact("manifest", ReadFromFile(filename, &m)); err != nil {
+		return nil, err
+	}
+	if err := m.checkVersion(); err != nil {
+		return nil, err
+	}
+	if m.Circuit.canonical() != circuit.canonical() {
+		return nil, fmt.Errorf("artifacts at %s are for the %s circuit, not %s", filename, m.Circuit.canonical(), circuit.canonical())
+	}
+	if m.Curve.canonical() != curve.canonical() {
+		return nil, fmt.Errorf("artifacts at %s are for curve %s, not %s", filename, m.Curve, curve.canonical())
+	}
+	if _, err := ParseBackend(string(m.Backend)); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest %s: %w", ErrArtifactCorrupt, filename, err)
+	}
+	if m.Circuit.canonical() == CircuitMessage && m.MaxMessageLen <= 0 {
+		return nil, fmt.Errorf("%w: invalid manifest %s: missing maximum message length", ErrArtifactCorrupt, filename)
+	}
+	if m.Circuit.canonical() == CircuitBatch && (m.BatchSize <= 0 || m.CommitmentHash == "") {
+		return nil, fmt.Errorf("%w: invalid manifest %s: missing batch size or commitment hash", ErrArtifactCorrupt, filename)
+	}
+	if m.Circuit.canonical() == CircuitMembership && (m.MerkleDepth <= 0 || m.CommitmentHash == "") {
+		return nil, fmt.Errorf("%w: invalid manifest %s: missing Merkle tree depth or hash", ErrArtifactCorrupt, filename)
+	}
+	if m.Circuit.canonical() == CircuitNullifier && m.CommitmentHash == "" {
+		return nil, fmt.Errorf("%w: invalid manifest %s: missing nullifier hash", ErrArtifactCorrupt, filename)
+	}
+	if m.Circuit.canonical() == CircuitThreshold && (m.Signers <= 0 || m.Threshold < 0 || m.Threshold > m.Signers) {
+		return nil, fmt.Errorf("%w: invalid manifest %s: invalid threshold %d of %d signers", ErrArtifactCorrupt, filename, m.Threshold, m.Signers)
+	}
+	if m.CommitmentHash != "" {
+		if _, err := ParseFieldHash(string(m.CommitmentHash)); err != nil {
+			return nil, fmt.Errorf("%w: invalid manifest %s: %w", ErrArtifactCorrupt, filename, err)
+		}
+		m.CommitmentHash = m.CommitmentHash.canonical()
+	}
+	m.Circuit, m.Curve, m.Backend = m.Circuit.canonical(), m.Curve.canonical(), m.Backend.canonical()
+	return &m, nil
+}
+
+// WriteManifest writes the manifest as indented JSON.
+func WriteManifest(filename string, m *Manifest) error {
+	manifestJSON, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling manifest JSON: %w", err)
+	}
+	return WriteToFile(filename, bytes.NewReader(manifestJSON))
+}
+
+// Artifacts groups the compiled circuit and its proving and verifying keys.
+type Artifacts struct {
+	Circuit CircuitType
+	Curve   Curve
+	Backend Backend
+	CCS     constraint.ConstraintSystem
+	PK      ProvingKey
+	VK      VerifyingKey
+
+	KeyEncoding KeyEncoding // Encoding of the proving key file, as read or to write; KeyCompressed if empty
+
+	MaxMessageLen  int       // Message circuit only: message capacity in bytes
+	BatchSize      int       // Batch circuit only: signatures per proof
+	MerkleDepth    int       // Membership circuit only: depth of the key tree
+	Signers        int       // Threshold circuit only: number of keys n
+	Threshold      int       // Threshold circuit only: signatures required k, zero if it is a public input
+	CommitmentHash FieldHash // Message, batch, membership, nullifier and threshold circuits: hash of the commitment, key tree or nullifier, none if empty
+}
+
+// checkFiles checks the artifact files against their digests in the
+// manifest: the constraint system if prover is set, the verifying key if
+// verifier is set. The proving key is checked as it is read, by
+// readProvingKey.
+func (m *Manifest) checkFiles(paths Paths, prover, verifier bool) error {
+	if prover {
+		if err := m.checkFile("constraint system", paths.R1CS, m.SHA256.R1CS); err != nil {
+			return err
+		}
+	}
+	if verifier {
+		return m.checkFile("verifying key", paths.VerifyingKey, m.SHA256.VerifyingKey)
+	}
+	return nil
+}
+
+// PublicInputs reports whether the artifacts were compiled from a circuit with
+// public inputs.
+func (a *Artifacts) PublicInputs() bool {
+	return HasPublicInputs(a.CCS)
+}
+
+// LoadArtifacts reads the constraint system and both keys, for the backend
+// recorded in the manifest and the encoding recorded in the proving key. The
+// files are checked against the digests of the manifest, the proving key as
+// it is read, so a stale or modified artifact is reported as such rather than
+// failing to prove.
+func LoadArtifacts(paths Paths) (*Artifacts, error) {
+	start := time.Now()
+	a, err := loadArtifacts(paths)
+	recordLoad("artifacts", paths.Resolve(), start, err)
+	return a, err
+}
+
+func loadArtifacts(paths Paths) (*Artifacts, error) {
+	if _, err := ParseCurve(string(paths.Curve)); err != nil {
+		return nil, err
+	}
+	paths = paths.Resolve()
+	manifest, err := paths.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	a := &Artifacts{
+		Circuit:        paths.Circuit,
+		Curve:          paths.Curve,
+		Backend:        manifest.Backend,
+		MaxMessageLen:  manifest.MaxMessageLen,
+		BatchSize:      manifest.BatchSize,
+		MerkleDepth:    manifest.MerkleDepth,
+		Signers:        manifest.Signers,
+		Threshold:      manifest.Threshold,
+		CommitmentHash: manifest.CommitmentHash,
+	}
+	if a.CCS, err = manifest.Backend.newCS(); err != nil {
+		return nil, err
+	}
+	if a.VK, err = manifest.Backend.newVerifyingKey(); err != nil {
+		return nil, err
+	}
+	if err := manifest.checkFiles(paths, true, true); err != nil {
+		return nil, err
+	}
+	if err := readArtifact("constraint system", paths.R1CS, a.CCS); err != nil {
+		return nil, err
+	}
+	if err := manifest.checkConstraints(a.CCS); err != nil {
+		return nil, err
+	}
+	if a.PK, a.KeyEncoding, err = manifest.readProvingKey(paths.ProvingKey); err != nil {
+		return nil, err
+	}
+	if err := readArtifact("verifying key", paths.VerifyingKey, a.VK); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// LoadVerifyingKey reads only the verifying key, which is all a verifier needs.
+func LoadVerifyingKey(paths Paths) (VerifyingKey, error) {
+	start := time.Now()
+	vk, err := loadVerifyingKey(paths)
+	recordLoad("verifying_key", paths.Resolve(), start, err)
+	return vk, err
+}
+
+func loadVerifyingKey(paths Paths) (VerifyingKey, error) {
+	paths = paths.Resolve()
+	manifest, err := paths.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.checkFiles(paths, false, true); err != nil {
+		return nil, err
+	}
+	vk, err := manifest.Backend.newVerifyingKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := readArtifact("verifying key", paths.VerifyingKey, vk); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// Save writes the constraint system, both keys and the manifest in the
+// namespace of a.Circuit and a.Curve, creating it if needed. The manifest
+// records the digest of each file and the gnark version. The proving key
+// is written with a.KeyEncoding, so loading artifacts and saving them again
+// with KeyRaw converts them.
+func (a *Artifacts) Save(paths Paths) error {
+	paths.Circuit, paths.Curve, paths.BatchSize = a.Circuit, a.Curve, a.BatchSize
+	paths = paths.Resolve()
+	if err := os.MkdirAll(paths.Namespace(), 0o755); err != nil {
+		return fmt.Errorf("error creating artifact directory %s: %w", paths.Namespace(), err)
+	}
+	if err := WriteToFile(paths.R1CS, a.CCS); err != nil {
+		return err
+	}
+	if err := WriteProvingKey(paths.ProvingKey, a.PK, a.KeyEncoding); err != nil {
+		return err
+	}
+	if err := WriteToFile(paths.VerifyingKey, a.VK); err != nil {
+		return err
+	}
+	var digests ArtifactDigests
+	for _, file := range []struct {
+		digest   *string
+		filename string
+	}{{&digests.R1CS, paths.R1CS}, {&digests.ProvingKey, paths.ProvingKey}, {&digests.VerifyingKey, paths.VerifyingKey}} {
+		var err error
+		if *file.digest, err = FileDigest(file.filename); err != nil {
+			return err
+		}
+	}
+	return WriteManifest(paths.Manifest, &Manifest{
+		Circuit:        a.Circuit.canonical(),
+		Curve:          a.Curve.canonical(),
+		Backend:        a.Backend.canonical(),
+		MaxMessageLen:  a.MaxMessageLen,
+		BatchSize:      a.BatchSize,
+		MerkleDepth:    a.MerkleDepth,
+		Signers:        a.Signers,
+		Threshold:      a.Threshold,
+		CommitmentHash: a.CommitmentHash,
+		GnarkVersion:   GnarkVersion(),
+		Constraints:    a.CCS.GetNbConstraints(),
+		SHA256:         digests,
+	})
+}
+
+// WriteInput writes the witness input as indented JSON.
+func WriteInput(filename string, input Input) error {
+	proveInputJSON, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling prove input JSON: %w", err)
+	}
+	return WriteToFile(filename, bytes.NewReader(proveInputJSON))
+}
+
+// ReadInput reads a witness input written by WriteInput.
+func ReadInput(filename string) (*ProveInputEcdsa, error) {
+	var input ProveInputEcdsa
+	if err := readArtifact("witness input", filename, &input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// ReadCircuitInput reads a witness input written by WriteInput for circuit.
+func ReadCircuitInput(circuit CircuitType, filename string) (Input, error) {
+	input, err := NewCircuitInput(circuit)
+	if err != nil {
+		return nil, err
+	}
+	if err := readArtifact("witness input", filename, input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// NewCircuitInput returns an empty witness input of circuit to decode JSON
+// into.
+func NewCircuitInput(circuit CircuitType) (Input, error) {
+	switch circuit.canonical() {
+	case CircuitECDSA:
+		return &ProveInputEcdsa{}, nil
+	case CircuitWebAuthn:
+		return &WebAuthnInput{}, nil
+	case CircuitMessage:
+		return &MessageInput{}, nil
+	case CircuitBatch:
+		return &BatchInput{}, nil
+	case CircuitMembership:
+		return &MembershipInput{}, nil
+	case CircuitNullifier:
+		return &NullifierInput{}, nil
+	case CircuitThreshold:
+		return &ThresholdInput{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported circuit type %q", circuit)
+	}
+}
+
+// WriteToFile serializes gnark objects or byte readers to a file.
+func WriteToFile(filename string, data interface{}) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	switch v := data.(type) {
+	case io.WriterTo:
+		_, err = v.WriteTo(file)
+	default:
+		err = fmt.Errorf("unsupported type for writing to file: %T", data)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing to file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// ReadFromFile deserializes gnark objects or JSON inputs from a file.
+func ReadFromFile(filename string, data interface{}) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	switch v := data.(type) {
+	case io.ReaderFrom:
+		_, err = v.ReadFrom(file)
+		if err != nil && err != io.EOF { // io.EOF is expected if the file is empty or partially read
+			return fmt.Errorf("error reading from file %s into io.ReaderFrom: %w", filename, err)
+		}
+	case *ProveInputEcdsa, *WebAuthnInput, *MessageInput, *BatchInput, *MembershipInput, *NullifierInput, *ThresholdInput, *Manifest: // For the JSON inputs and manifest
+		decoder := json.NewDecoder(file)
+		err = decoder.Decode(v)
+		if err != nil {
+			return fmt.Errorf("error decoding JSON from file %s: %w", filename, err)
+		}
+	default:
+		return fmt.Errorf("unsupported type for reading from file: %T", data)
+	}
+
+	return nil
+}
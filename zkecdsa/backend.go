@@ -0,0 +1,161 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/plonk"
+	plonkbn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Backend selects the proof system. Groth16 needs a circuit-specific trusted
+// setup; PLONK only needs a universal KZG SRS.
+type Backend string
+
+const (
+	Groth16 Backend = "groth16"
+	Plonk   Backend = "plonk"
+)
+
+// Backends lists the supported backends.
+var Backends = []Backend{Groth16, Plonk}
+
+// ParseBackend parses a backend name. The empty string selects Groth16.
+func ParseBackend(name string) (Backend, error) {
+	switch strings.ToLower(name) {
+	case "", "groth16":
+		return Groth16, nil
+	case "plonk":
+		return Plonk, nil
+	default:
+		return "", fmt.Errorf("unsupported backend %q (supported: %s, %s)", name, Groth16, Plonk)
+	}
+}
+
+// canonical returns the canonical name of b, Groth16 if b is unset, or b
+// unchanged if it is not a supported backend.
+func (b Backend) canonical() Backend {
+	if parsed, err := ParseBackend(string(b)); err == nil {
+		return parsed
+	}
+	return b
+}
+
+// Proof is a Groth16 or PLONK proof over BN254.
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+}
+
+// ProvingKey is a Groth16 or PLONK proving key over BN254.
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+}
+
+// VerifyingKey is a Groth16 or PLONK verifying key over BN254.
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	solidity.VerifyingKey
+}
+
+// BackendOf returns the backend a proof, proving key or verifying key belongs to.
+func BackendOf(v interface{}) (Backend, error) {
+	switch v.(type) {
+	case *groth16bn254.Proof, *groth16bn254.ProvingKey, *groth16bn254.VerifyingKey:
+		return Groth16, nil
+	case *plonkbn254.Proof, *plonkbn254.ProvingKey, *plonkbn254.VerifyingKey:
+		return Plonk, nil
+	default:
+		return "", fmt.Errorf("unsupported proof system object %T", v)
+	}
+}
+
+// id returns the gnark identifier of the backend.
+func (b Backend) id() backend.ID {
+	switch b.canonical() {
+	case Groth16:
+		return backend.GROTH16
+	case Plonk:
+		return backend.PLONK
+	default:
+		return backend.UNKNOWN
+	}
+}
+
+// builder returns the constraint system builder the backend proves over.
+func (b Backend) builder() (frontend.NewBuilder, error) {
+	switch b.canonical() {
+	case Groth16:
+		return r1cs.NewBuilder, nil
+	case Plonk:
+		return scs.NewBuilder, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", b)
+	}
+}
+
+// newCS returns an empty constraint system to deserialize into.
+func (b Backend) newCS() (constraint.ConstraintSystem, error) {
+	switch b.canonical() {
+	case Groth16:
+		return groth16.NewCS(ecc.BN254), nil
+	case Plonk:
+		return plonk.NewCS(ecc.BN254), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", b)
+	}
+}
+
+// newProvingKey returns an empty proving key to deserialize into.
+func (b Backend) newProvingKey() (ProvingKey, error) {
+	switch b.canonical() {
+	case Groth16:
+		return groth16.NewProvingKey(ecc.BN254), nil
+	case Plonk:
+		return plonk.NewProvingKey(ecc.BN254), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", b)
+	}
+}
+
+// newVerifyingKey returns an empty verifying key to deserialize into.
+func (b Backend) newVerifyingKey() (VerifyingKey, error) {
+	switch b.canonical() {
+	case Groth16:
+		return groth16.NewVerifyingKey(ecc.BN254), nil
+	case Plonk:
+		return plonk.NewVerifyingKey(ecc.BN254), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", b)
+	}
+}
+
+// newProof returns an empty proof to deserialize into.
+func (b Backend) newProof() (Proof, error) {
+	switch b.canonical() {
+	case Groth16:
+		return groth16.NewProof(ecc.BN254), nil
+	case Plonk:
+		return plonk.NewProof(ecc.BN254), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %q", b)
+	}
+}
@@ -0,0 +1,133 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// DefaultBatchSize is the number of signatures of the batch circuit when none
+// is configured.
+const DefaultBatchSize = 8
+
+// BatchCircuit verifies len(Sigs) ECDSA signatures, each of Msgs[i] under
+// Pubs[i], in a single proof. The message hashes and public keys are bound to
+// the single public Commitment, see BatchCommitment, so the proof has one
+// public input whatever the batch size.
+type BatchCircuit[T, S emulated.FieldParams] struct {
+	Sigs       []ecdsa.Signature[S]
+	Msgs       []emulated.Element[S]
+	Pubs       []ecdsa.PublicKey[T, S]
+	Commitment frontend.Variable `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *BatchCircuit[T, S]) Define(api frontend.API) error {
+	if len(c.Msgs) != len(c.Sigs) || len(c.Pubs) != len(c.Sigs) {
+		return fmt.Errorf("batch of %d signatures with %d messages and %d public keys", len(c.Sigs), len(c.Msgs), len(c.Pubs))
+	}
+	hasher, err := c.hash.newHasher(api)
+	if err != nil {
+		return err
+	}
+	for i := range c.Sigs {
+		signature := EcdsaCircuit[T, S]{Sig: c.Sigs[i], Msg: c.Msgs[i], Pub: c.Pubs[i]}
+		if err := signature.Define(api); err != nil {
+			return err
+		}
+		// The limbs are not reduced, but the verifier recomputes the
+		// commitment from canonical values, which a non-canonical
+		// representation would not match
+		hasher.Write(c.Msgs[i].Limbs...)
+		hasher.Write(c.Pubs[i].X.Limbs...)
+		hasher.Write(c.Pubs[i].Y.Limbs...)
+	}
+	api.AssertIsEqual(hasher.Sum(), c.Commitment)
+	return nil
+}
+
+// BatchCommitment computes off-circuit the commitment checked by
+// BatchCircuit: the hash of the message hash, public key X and public key Y
+// of every input in order, each split into the limbs of its emulated field.
+// Inputs without a curve are taken to be on curve.
+func BatchCommitment(hash FieldHash, curve Curve, inputs []ProveInputEcdsa) (*big.Int, error) {
+	decoded := make([]*decodedInput, len(inputs))
+	for i := range inputs {
+		if inputs[i].Curve != "" && inputs[i].Curve.canonical() != curve.canonical() {
+			return nil, fmt.Errorf("batch input %d is on curve %s, not %s", i, inputs[i].Curve, curve.canonical())
+		}
+		input := inputs[i]
+		input.Curve = curve
+		d, err := input.decodePublic()
+		if err != nil {
+			return nil, fmt.Errorf("batch input %d: %w", i, err)
+		}
+		decoded[i] = d
+	}
+	switch curve.canonical() {
+	case P256:
+		return batchCommitment[emulated.P256Fp, emulated.P256Fr](hash, decoded)
+	case Secp256k1:
+		return batchCommitment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](hash, decoded)
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func batchCommitment[T, S emulated.FieldParams](hash FieldHash, decoded []*decodedInput) (*big.Int, error) {
+	var elements []*big.Int
+	for _, d := range decoded {
+		elements = append(elements, limbs[S](new(big.Int).SetBytes(d.msgHash))...)
+		elements = append(elements, limbs[T](d.pubX)...)
+		elements = append(elements, limbs[T](d.pubY)...)
+	}
+	return hash.Sum(elements...)
+}
+
+// limbs splits v, reduced modulo the field of P, into the limbs of its
+// emulated element, least significant first, as a witness assignment does.
+func limbs[P emulated.FieldParams](v *big.Int) []*big.Int {
+	var fp P
+	nbLimbs, nbBits := emulated.GetEffectiveFieldParams[P](ecc.BN254.ScalarField())
+	reduced := new(big.Int).Mod(v, fp.Modulus())
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), nbBits), big.NewInt(1))
+	split := make([]*big.Int, nbLimbs)
+	for i := range split {
+		split[i] = new(big.Int).And(reduced, mask)
+		reduced.Rsh(reduced, nbBits)
+	}
+	return split
+}
+
+// NewBatchCircuit returns the empty batch circuit definition to compile for
+// curve, verifying size signatures and committing to them over hash.
+func NewBatchCircuit(curve Curve, size int, hash FieldHash) (frontend.Circuit, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid batch size %d", size)
+	}
+	if _, err := ParseFieldHash(string(hash)); err != nil {
+		return nil, err
+	}
+	switch curve.canonical() {
+	case P256:
+		return newBatchCircuit[emulated.P256Fp, emulated.P256Fr](size, hash.canonical()), nil
+	case Secp256k1:
+		return newBatchCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](size, hash.canonical()), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func newBatchCircuit[T, S emulated.FieldParams](size int, hash FieldHash) frontend.Circuit {
+	return &BatchCircuit[T, S]{
+		Sigs: make([]ecdsa.Signature[S], size),
+		Msgs: make([]emulated.Element[S], size),
+		Pubs: make([]ecdsa.PublicKey[T, S], size),
+		hash: hash,
+	}
+}
@@ -0,0 +1,195 @@
+package zkecdsa
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// BatchInput struct for JSON serialization of batch circuit witness inputs:
+// one ProveInputEcdsa per signature of the batch.
+type BatchInput struct {
+	Inputs []ProveInputEcdsa `json:"inputs"`          // The batch, in commitment order
+	Curve  Curve             `json:"curve,omitempty"` // Signature curve of every input, P256 if empty
+
+	// Filled in from the artifacts by Prover if unset
+	CommitmentHash FieldHash `json:"commitmentHash,omitempty"` // Hash of the commitment, MiMC if empty
+	Commitment     string    `json:"commitment,omitempty"`     // Hex string of the commitment, computed from Inputs if empty
+}
+
+// Circuit returns CircuitBatch.
+func (in *BatchInput) Circuit() CircuitType { return CircuitBatch }
+
+func (in *BatchInput) curve() Curve { return in.Curve }
+
+func (in *BatchInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// forCircuit returns in with the commitment hash of the batch circuit
+// compiled for size signatures over hash, checking that the batch has size
+// inputs. A hash already set in in must match.
+func (in *BatchInput) forCircuit(size int, hash FieldHash) (*BatchInput, error) {
+	if len(in.Inputs) != size {
+		return nil, fmt.Errorf("batch of %d signatures but the prover was loaded for %d", len(in.Inputs), size)
+	}
+	shaped := *in
+	if shaped.CommitmentHash == "" {
+		shaped.CommitmentHash = hash.canonical()
+	} else if shaped.CommitmentHash.canonical() != hash.canonical() {
+		return nil, fmt.Errorf("input commits with %s but the prover was loaded for %s", shaped.CommitmentHash, hash.canonical())
+	}
+	return &shaped, nil
+}
+
+// commitment returns the commitment of the batch, checking it matches
+// Commitment if set.
+func (in *BatchInput) commitment() (*big.Int, error) {
+	commitment, err := BatchCommitment(in.CommitmentHash, in.Curve, in.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	if in.Commitment != "" {
+		commitmentBytes, err := decodeHex("Commitment", in.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).SetBytes(commitmentBytes).Cmp(commitment) != 0 {
+			return nil, fmt.Errorf("batch does not match the commitment")
+		}
+	}
+	return commitment, nil
+}
+
+// decode decodes every input of the batch.
+func (in *BatchInput) decode() ([]*decodedInput, error) {
+	decoded := make([]*decodedInput, len(in.Inputs))
+	for i := range in.Inputs {
+		if in.Inputs[i].Curve != "" && in.Inputs[i].Curve.canonical() != in.Curve.canonical() {
+			return nil, fmt.Errorf("batch input %d is on curve %s, not %s", i, in.Inputs[i].Curve, in.Curve.canonical())
+		}
+		input := in.Inputs[i]
+		input.Curve = in.Curve
+		d, err := input.decode()
+		if err != nil {
+			return nil, fmt.Errorf("batch input %d: %w", i, err)
+		}
+		decoded[i] = d
+	}
+	return decoded, nil
+}
+
+func (in *BatchInput) signatureS() ([]*big.Int, error) {
+	decoded, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	sigS := make([]*big.Int, len(decoded))
+	for i, d := range decoded {
+		sigS[i] = d.s
+	}
+	return sigS, nil
+}
+
+// Assignment builds the full BatchCircuit assignment over the input curve.
+// publicInputs is ignored, the commitment is the only public input.
+func (in *BatchInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	commitment, err := in.commitment()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return batchAssignment[emulated.P256Fp, emulated.P256Fr](commitment, decoded), nil
+	case Secp256k1:
+		return batchAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](commitment, decoded), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the BatchCircuit assignment of the commitment,
+// taken from Commitment if set or else computed from the message hashes and
+// public keys of Inputs. The signatures are ignored.
+func (in *BatchInput) PublicAssignment() (frontend.Circuit, error) {
+	var commitment *big.Int
+	if in.Commitment != "" {
+		commitmentBytes, err := decodeHex("Commitment", in.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		commitment = new(big.Int).SetBytes(commitmentBytes)
+	} else {
+		var err error
+		if commitment, err = BatchCommitment(in.CommitmentHash, in.Curve, in.Inputs); err != nil {
+			return nil, err
+		}
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return batchPublicAssignment[emulated.P256Fp, emulated.P256Fr](len(in.Inputs), commitment), nil
+	case Secp256k1:
+		return batchPublicAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](len(in.Inputs), commitment), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func batchAssignment[T, S emulated.FieldParams](commitment *big.Int, decoded []*decodedInput) frontend.Circuit {
+	c := &BatchCircuit[T, S]{
+		Sigs:       make([]ecdsa.Signature[S], len(decoded)),
+		Msgs:       make([]emulated.Element[S], len(decoded)),
+		Pubs:       make([]ecdsa.PublicKey[T, S], len(decoded)),
+		Commitment: commitment,
+	}
+	for i, d := range decoded {
+		c.Sigs[i] = ecdsa.Signature[S]{
+			R: emulated.ValueOf[S](d.r),
+			S: emulated.ValueOf[S](d.s),
+		}
+		c.Msgs[i] = emulated.ValueOf[S](d.msgHash)
+		c.Pubs[i] = ecdsa.PublicKey[T, S]{
+			X: emulated.ValueOf[T](d.pubX),
+			Y: emulated.ValueOf[T](d.pubY),
+		}
+	}
+	return c
+}
+
+func batchPublicAssignment[T, S emulated.FieldParams](size int, commitment *big.Int) frontend.Circuit {
+	return &BatchCircuit[T, S]{
+		Sigs:       make([]ecdsa.Signature[S], size),
+		Msgs:       make([]emulated.Element[S], size),
+		Pubs:       make([]ecdsa.PublicKey[T, S], size),
+		Commitment: commitment,
+	}
+}
+
+// GenerateBatchInput signs size messages, each with a fresh key on curve, and
+// returns the batch circuit witness input committing over hash.
+func GenerateBatchInput(curve Curve, size int, hash FieldHash) (*BatchInput, error) {
+	in := &BatchInput{Curve: curve.canonical(), CommitmentHash: hash.canonical()}
+	for i := 0; i < size; i++ {
+		signed, err := GenerateInput(curve, []byte(fmt.Sprintf("hello world %d", i)))
+		if err != nil {
+			return nil, err
+		}
+		in.Inputs = append(in.Inputs, *signed)
+	}
+	commitment, err := BatchCommitment(in.CommitmentHash, in.Curve, in.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	in.Commitment = hex.EncodeToString(commitment.Bytes())
+	return in, nil
+}
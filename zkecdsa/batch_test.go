@@ -0,0 +1,69 @@
+package zkecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type p256Batch = BatchCircuit[emulated.P256Fp, emulated.P256Fr]
+
+func TestBatchCircuit(t *testing.T) {
+	const size = 2
+	in, err := GenerateBatchInput(P256, size, MiMC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered := []ProveInputEcdsa{in.Inputs[1], in.Inputs[0]}
+	reorderedCommitment, err := BatchCommitment(MiMC, P256, reordered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateInput(P256, []byte("other message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherS, _ := new(big.Int).SetString(other.S, 16)
+	tests := []struct {
+		name  string
+		edit  func(c *p256Batch)
+		valid bool
+	}{
+		{"batch", func(c *p256Batch) {}, true},
+		{"wrong commitment", func(c *p256Batch) {
+			c.Commitment = new(big.Int).Add(c.Commitment.(*big.Int), big.NewInt(1))
+		}, false},
+		{"commitment of the reordered batch", func(c *p256Batch) {
+			c.Commitment = reorderedCommitment
+		}, false},
+		{"one bad signature", func(c *p256Batch) {
+			c.Sigs[1].S = emulated.ValueOf[emulated.P256Fr](otherS)
+		}, false},
+		{"signatures swapped", func(c *p256Batch) {
+			c.Sigs[0], c.Sigs[1] = c.Sigs[1], c.Sigs[0]
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, err := NewBatchCircuit(P256, size, MiMC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assignment, err := in.Assignment(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.edit(assignment.(*p256Batch))
+			err = test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package zkecdsa proves knowledge of a valid P-256 or secp256k1 ECDSA
+// signature with gnark, over a message hash, over a message hashed in-circuit
+// or as part of a WebAuthn assertion, of batches of ECDSA signatures, of
+// signatures by a member of a key set, of signatures by a private key
+// disclosing only a per-scope nullifier and of k-of-n threshold signatures.
+//
+// It holds the circuits, the witness inputs and the Setup / Prove / Verify
+// pipeline shared by the generator, the cgo library and Go services.
+package zkecdsa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// CircuitType selects the statement being proven.
+type CircuitType string
+
+const (
+	CircuitECDSA      CircuitType = "ecdsa"      // EcdsaCircuit / EcdsaPublicCircuit
+	CircuitWebAuthn   CircuitType = "webauthn"   // WebAuthnCircuit
+	CircuitMessage    CircuitType = "message"    // MessageCircuit / MessagePublicCircuit / MessageCommitmentCircuit
+	CircuitBatch      CircuitType = "batch"      // BatchCircuit
+	CircuitMembership CircuitType = "membership" // MembershipCircuit
+	CircuitNullifier  CircuitType = "nullifier"  // NullifierCircuit
+	CircuitThreshold  CircuitType = "threshold"  // ThresholdCircuit and its public threshold and commitment variants
+)
+
+// CircuitTypes lists the supported circuit types.
+var CircuitTypes = []CircuitType{CircuitECDSA, CircuitWebAuthn, CircuitMessage, CircuitBatch, CircuitMembership, CircuitNullifier, CircuitThreshold}
+
+// ParseCircuitType parses a circuit type name. The empty string selects CircuitECDSA.
+func ParseCircuitType(name string) (CircuitType, error) {
+	switch strings.ToLower(name) {
+	case "", "ecdsa":
+		return CircuitECDSA, nil
+	case "webauthn", "passkey":
+		return CircuitWebAuthn, nil
+	case "message":
+		return CircuitMessage, nil
+	case "batch":
+		return CircuitBatch, nil
+	case "membership":
+		return CircuitMembership, nil
+	case "nullifier":
+		return CircuitNullifier, nil
+	case "threshold", "multisig":
+		return CircuitThreshold, nil
+	default:
+		return "", fmt.Errorf("unsupported circuit %q (supported: %s, %s, %s, %s, %s, %s, %s)", name, CircuitECDSA, CircuitWebAuthn, CircuitMessage, CircuitBatch, CircuitMembership, CircuitNullifier, CircuitThreshold)
+	}
+}
+
+// canonical returns the canonical name of t, CircuitECDSA if t is unset, or t
+// unchanged if it is not a supported circuit type.
+func (t CircuitType) canonical() CircuitType {
+	if parsed, err := ParseCircuitType(string(t)); err == nil {
+		return parsed
+	}
+	return t
+}
+
+// EcdsaCircuit verifies an ECDSA signature with every input kept private.
+type EcdsaCircuit[T, S emulated.FieldParams] struct {
+	Sig ecdsa.Signature[S]
+	Msg emulated.Element[S]
+	Pub ecdsa.PublicKey[T, S]
+}
+
+func (c *EcdsaCircuit[T, S]) Define(api frontend.API) error {
+	curveParams := sw_emulated.GetCurveParams[T]()
+	c.Pub.Verify(api, curveParams, &c.Msg, &c.Sig)
+	return nil
+}
+
+// EcdsaPublicCircuit is the variant of EcdsaCircuit where the message hash and
+// the public key are public inputs, so a proof is bound to a signer and message.
+type EcdsaPublicCircuit[T, S emulated.FieldParams] struct {
+	Sig ecdsa.Signature[S]
+	Msg emulated.Element[S]   `gnark:",public"`
+	Pub ecdsa.PublicKey[T, S] `gnark:",public"`
+}
+
+func (c *EcdsaPublicCircuit[T, S]) Define(api frontend.API) error {
+	curveParams := sw_emulated.GetCurveParams[T]()
+	c.Pub.Verify(api, curveParams, &c.Msg, &c.Sig)
+	return nil
+}
+
+// NewCircuit returns the empty circuit definition to compile for curve.
+func NewCircuit(curve Curve, publicInputs bool) (frontend.Circuit, error) {
+	switch curve.canonical() {
+	case P256:
+		return newCircuit[emulated.P256Fp, emulated.P256Fr](publicInputs), nil
+	case Secp256k1:
+		return newCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](publicInputs), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func newCircuit[T, S emulated.FieldParams](publicInputs bool) frontend.Circuit {
+	if publicInputs {
+		return &EcdsaPublicCircuit[T, S]{}
+	}
+	return &EcdsaCircuit[T, S]{}
+}
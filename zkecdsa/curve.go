@@ -0,0 +1,39 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Curve selects the curve the ECDSA signature is over. The proof itself is
+// always over BN254; only the emulated signature arithmetic changes.
+type Curve string
+
+const (
+	P256      Curve = "p256"      // NIST P-256 / secp256r1 (passkeys, WebAuthn)
+	Secp256k1 Curve = "secp256k1" // Ethereum and Bitcoin
+)
+
+// Curves lists the supported curves.
+var Curves = []Curve{P256, Secp256k1}
+
+// ParseCurve parses a curve name. The empty string selects P256.
+func ParseCurve(name string) (Curve, error) {
+	switch strings.ToLower(name) {
+	case "", "p256", "p-256", "secp256r1", "prime256v1":
+		return P256, nil
+	case "secp256k1":
+		return Secp256k1, nil
+	default:
+		return "", fmt.Errorf("unsupported curve %q (supported: %s, %s)", name, P256, Secp256k1)
+	}
+}
+
+// canonical returns the canonical name of c, P256 if c is unset, or c
+// unchanged if it is not a supported curve.
+func (c Curve) canonical() Curve {
+	if parsed, err := ParseCurve(string(c)); err == nil {
+		return parsed
+	}
+	return c
+}
@@ -0,0 +1,88 @@
+package zkecdsa
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// MarshalProof serializes a proof to bytes.
+func MarshalProof(proof Proof) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("error serializing proof: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalProof deserializes a proof of the given backend written by
+// MarshalProof, reporting malformed bytes as ErrInvalidInput. The backend of
+// a verifying key is given by BackendOf.
+func UnmarshalProof(backend Backend, b []byte) (Proof, error) {
+	proof, err := backend.newProof()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := proof.ReadFrom(bytes.NewReader(b)); err != nil {
+		return nil, invalidInput(fmt.Errorf("error deserializing proof: %w", err))
+	}
+	return proof, nil
+}
+
+// MarshalVerifyingKey serializes a verifying key to bytes.
+func MarshalVerifyingKey(vk VerifyingKey) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("error serializing verifying key: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalVerifyingKey deserializes a verifying key, e.g. the content of
+// verifying_key.bin. The backend is detected from the encoding: the curve
+// points of each key are checked on decoding, so a key of one backend does
+// not decode as a key of the other. Malformed bytes are reported as
+// ErrArtifactCorrupt.
+func UnmarshalVerifyingKey(b []byte) (VerifyingKey, error) {
+	var errs []string
+	for _, backend := range Backends {
+		vk, err := backend.newVerifyingKey()
+		if err != nil {
+			return nil, err
+		}
+		n, err := vk.ReadFrom(bytes.NewReader(b))
+		if err == nil && n == int64(len(b)) {
+			return vk, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%d trailing bytes", int64(len(b))-n)
+		}
+		errs = append(errs, fmt.Sprintf("as %s: %v", backend, err))
+	}
+	return nil, fmt.Errorf("%w: error deserializing verifying key: %s", ErrArtifactCorrupt, strings.Join(errs, "; "))
+}
+
+// MarshalPublicWitness serializes a public witness to bytes.
+func MarshalPublicWitness(publicWitness witness.Witness) ([]byte, error) {
+	b, err := publicWitness.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("error serializing public witness: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalPublicWitness deserializes a public witness written by
+// MarshalPublicWitness, reporting malformed bytes as ErrInvalidInput.
+func UnmarshalPublicWitness(b []byte) (witness.Witness, error) {
+	publicWitness, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("error creating public witness: %w", err)
+	}
+	if err := publicWitness.UnmarshalBinary(b); err != nil {
+		return nil, invalidInput(fmt.Errorf("error deserializing public witness: %w", err))
+	}
+	return publicWitness, nil
+}
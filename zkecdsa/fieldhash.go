@@ -0,0 +1,105 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	"github.com/consensys/gnark/frontend"
+	stdhash "github.com/consensys/gnark/std/hash"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	permposeidon2 "github.com/consensys/gnark/std/permutation/poseidon2"
+)
+
+// FieldHash selects the SNARK-friendly hash used for commitments over the
+// BN254 scalar field. Both are cheap in-circuit compared to SHA-256.
+type FieldHash string
+
+const (
+	MiMC      FieldHash = "mimc"
+	Poseidon2 FieldHash = "poseidon2"
+)
+
+// FieldHashes lists the supported field hashes.
+var FieldHashes = []FieldHash{MiMC, Poseidon2}
+
+// ParseFieldHash parses a field hash name. The empty string selects MiMC.
+func ParseFieldHash(name string) (FieldHash, error) {
+	switch strings.ToLower(name) {
+	case "", "mimc":
+		return MiMC, nil
+	case "poseidon2":
+		return Poseidon2, nil
+	default:
+		return "", fmt.Errorf("unsupported field hash %q (supported: %s, %s)", name, MiMC, Poseidon2)
+	}
+}
+
+// canonical returns the canonical name of h, MiMC if h is unset, or h
+// unchanged if it is not a supported field hash.
+func (h FieldHash) canonical() FieldHash {
+	if parsed, err := ParseFieldHash(string(h)); err == nil {
+		return parsed
+	}
+	return h
+}
+
+// Poseidon2 parameters of gnark-crypto's default BN254 Merkle-Damgard hasher:
+// width 2, 6 full rounds and 50 partial rounds.
+const (
+	poseidon2Width         = 2
+	poseidon2FullRounds    = 6
+	poseidon2PartialRounds = 50
+	poseidon2InitialState  = 0
+)
+
+// newHasher returns the in-circuit hasher, matching Sum.
+func (h FieldHash) newHasher(api frontend.API) (stdhash.FieldHasher, error) {
+	switch h.canonical() {
+	case MiMC:
+		return stdmimc.New(api)
+	case Poseidon2:
+		perm, err := permposeidon2.NewPoseidon2FromParameters(api, poseidon2Width, poseidon2FullRounds, poseidon2PartialRounds)
+		if err != nil {
+			return nil, err
+		}
+		return stdhash.NewMerkleDamgardHasher(api, perm, poseidon2InitialState), nil
+	default:
+		return nil, fmt.Errorf("unsupported field hash %q", h)
+	}
+}
+
+// newNative returns the hasher computing the same function off-circuit.
+func (h FieldHash) newNative() (hash.Hash, error) {
+	switch h.canonical() {
+	case MiMC:
+		return mimc.NewMiMC(), nil
+	case Poseidon2:
+		return poseidon2.NewMerkleDamgardHasher(), nil
+	default:
+		return nil, fmt.Errorf("unsupported field hash %q", h)
+	}
+}
+
+// Sum hashes field elements off-circuit, as the in-circuit hasher does with
+// the same elements written in order. Each element is reduced modulo the
+// BN254 scalar field.
+func (h FieldHash) Sum(elements ...*big.Int) (*big.Int, error) {
+	hasher, err := h.newNative()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range elements {
+		var elem fr.Element
+		elem.SetBigInt(e)
+		b := elem.Bytes()
+		if _, err := hasher.Write(b[:]); err != nil {
+			return nil, fmt.Errorf("error hashing with %s: %w", h.canonical(), err)
+		}
+	}
+	return new(big.Int).SetBytes(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,323 @@
+package zkecdsa
+
+import (
+	cryptoecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	secp256k1ecdsa "github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// Input is the witness input of one of the circuits, such as *ProveInputEcdsa or
+// *WebAuthnInput.
+type Input interface {
+	// Circuit returns the circuit the input is a witness for.
+	Circuit() CircuitType
+	// Assignment builds the full witness assignment over the input curve.
+	// publicInputs selects the circuit variant where there is one.
+	Assignment(publicInputs bool) (frontend.Circuit, error)
+	// PublicAssignment builds the assignment of the public inputs alone, as a
+	// relying party would from what it knows.
+	PublicAssignment() (frontend.Circuit, error)
+
+	// signatureS returns the S of every signature of the input, nil for a
+	// signature slot left empty.
+	signatureS() ([]*big.Int, error)
+	curve() Curve
+	withCurve(curve Curve) Input
+}
+
+// ProveInputEcdsa struct for JSON serialization of witness inputs. The
+// signature is given either by R and S or by Signature, the public key either
+// by PubX and PubY or by PublicKey.
+type ProveInputEcdsa struct {
+	MsgHash   string `json:"msgHash"`             // Hex string of the message hash
+	R         string `json:"r,omitempty"`         // Hex string of signature R
+	S         string `json:"s,omitempty"`         // Hex string of signature S
+	Signature string `json:"signature,omitempty"` // Hex string of the ASN.1 DER signature, as output by OpenSSL, HSMs and WebCrypto
+	PubX      string `json:"pubX,omitempty"`      // Hex string of public key X
+	PubY      string `json:"pubY,omitempty"`      // Hex string of public key Y
+	PublicKey string `json:"publicKey,omitempty"` // Public key as a SEC1 point in hex, compressed or not, a PKIX PEM block or a JWK
+	Curve     Curve  `json:"curve,omitempty"`     // Signature curve, P256 if empty
+}
+
+// Circuit returns CircuitECDSA.
+func (in *ProveInputEcdsa) Circuit() CircuitType { return CircuitECDSA }
+
+func (in *ProveInputEcdsa) curve() Curve { return in.Curve }
+
+func (in *ProveInputEcdsa) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// decodedInput holds the values of a ProveInputEcdsa once the hex is decoded.
+type decodedInput struct {
+	msgHash    []byte
+	r, s       *big.Int
+	pubX, pubY *big.Int
+}
+
+// decodeHex decodes a single hex field, naming it in the error.
+func decodeHex(name, value string) ([]byte, error) {
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, &InputError{Field: name, Err: fmt.Errorf("%w: %v", ErrInvalidHex, err)}
+	}
+	return b, nil
+}
+
+// decodePublic decodes the fields that the public circuit variant exposes.
+func (in *ProveInputEcdsa) decodePublic() (*decodedInput, error) {
+	msgHashBytes, err := decodeFixed("MsgHash", in.MsgHash, MsgHashLen)
+	if err != nil {
+		return nil, err
+	}
+	pubX, pubY, err := in.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &decodedInput{msgHash: msgHashBytes, pubX: pubX, pubY: pubY}, nil
+}
+
+// publicKey decodes PublicKey if set, or else PubX and PubY.
+func (in *ProveInputEcdsa) publicKey() (*big.Int, *big.Int, error) {
+	if in.PublicKey == "" {
+		return decodePoint(in.Curve, "", in.PubX, in.PubY)
+	}
+	if in.PubX != "" || in.PubY != "" {
+		return nil, nil, &InputError{Field: "PublicKey", Err: fmt.Errorf("%w: set along with PubX and PubY", ErrInvalidEncoding)}
+	}
+	return decodePublicKey(in.Curve, "PublicKey", in.PublicKey)
+}
+
+// signature decodes Signature if set, or else R and S.
+func (in *ProveInputEcdsa) signature() (*big.Int, *big.Int, error) {
+	if in.Signature != "" {
+		if in.R != "" || in.S != "" {
+			return nil, nil, &InputError{Field: "Signature", Err: fmt.Errorf("%w: set along with R and S", ErrInvalidEncoding)}
+		}
+		return decodeDERSignature(in.Curve, "Signature", in.Signature)
+	}
+	r, err := decodeScalar(in.Curve, "R", in.R)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := decodeScalar(in.Curve, "S", in.S)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, s, nil
+}
+
+// decode decodes every field of the input.
+func (in *ProveInputEcdsa) decode() (*decodedInput, error) {
+	r, s, err := in.signature()
+	if err != nil {
+		return nil, err
+	}
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	d.r, d.s = r, s
+	return d, nil
+}
+
+func (in *ProveInputEcdsa) signatureS() ([]*big.Int, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	return []*big.Int{d.s}, nil
+}
+
+// Assignment builds the full witness assignment for the selected circuit
+// variant over the input curve.
+func (in *ProveInputEcdsa) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return assignment[emulated.P256Fp, emulated.P256Fr](publicInputs, d), nil
+	case Secp256k1:
+		return assignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](publicInputs, d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the EcdsaPublicCircuit assignment from the message
+// hash and public key alone. R and S are ignored.
+func (in *ProveInputEcdsa) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return publicAssignment[emulated.P256Fp, emulated.P256Fr](d), nil
+	case Secp256k1:
+		return publicAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func assignment[T, S emulated.FieldParams](publicInputs bool, d *decodedInput) frontend.Circuit {
+	sig := ecdsa.Signature[S]{
+		R: emulated.ValueOf[S](d.r),
+		S: emulated.ValueOf[S](d.s),
+	}
+	msg := emulated.ValueOf[S](d.msgHash)
+	pub := ecdsa.PublicKey[T, S]{
+		X: emulated.ValueOf[T](d.pubX),
+		Y: emulated.ValueOf[T](d.pubY),
+	}
+	if publicInputs {
+		return &EcdsaPublicCircuit[T, S]{Sig: sig, Msg: msg, Pub: pub}
+	}
+	return &EcdsaCircuit[T, S]{Sig: sig, Msg: msg, Pub: pub}
+}
+
+func publicAssignment[T, S emulated.FieldParams](d *decodedInput) frontend.Circuit {
+	return &EcdsaPublicCircuit[T, S]{
+		Msg: emulated.ValueOf[S](d.msgHash),
+		Pub: ecdsa.PublicKey[T, S]{
+			X: emulated.ValueOf[T](d.pubX),
+			Y: emulated.ValueOf[T](d.pubY),
+		},
+	}
+}
+
+// GenerateInput signs sha256(msg) with a fresh key on curve and returns the
+// resulting witness input.
+func GenerateInput(curve Curve, msg []byte) (*ProveInputEcdsa, error) {
+	switch curve.canonical() {
+	case P256:
+		return generateP256Input(msg)
+	case Secp256k1:
+		return generateSecp256k1Input(msg)
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func generateP256Input(msg []byte) (*ProveInputEcdsa, error) {
+	privKey, err := cryptoecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	publicKey := privKey.PublicKey
+
+	msgHash := sha256.Sum256(msg)
+	sigBin, err := privKey.Sign(rand.Reader, msgHash[:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	r, s, err := parseDERSignature(sigBin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanity check before handing the values to the circuit
+	if !cryptoecdsa.Verify(&publicKey, msgHash[:], r, s) {
+		return nil, fmt.Errorf("generated signature verification failed")
+	}
+
+	return &ProveInputEcdsa{
+		MsgHash: hex.EncodeToString(msgHash[:]),
+		R:       hex.EncodeToString(r.FillBytes(make([]byte, scalarLen))),
+		S:       hex.EncodeToString(s.FillBytes(make([]byte, scalarLen))),
+		PubX:    hex.EncodeToString(publicKey.X.FillBytes(make([]byte, scalarLen))),
+		PubY:    hex.EncodeToString(publicKey.Y.FillBytes(make([]byte, scalarLen))),
+		Curve:   P256,
+	}, nil
+}
+
+func generateSecp256k1Input(msg []byte) (*ProveInputEcdsa, error) {
+	privKey, err := secp256k1ecdsa.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	publicKey := privKey.PublicKey
+
+	// Sign the pre-hashed message, as for P-256
+	msgHash := sha256.Sum256(msg)
+	sigBin, err := privKey.Sign(msgHash[:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	// Sanity check before handing the values to the circuit
+	if ok, err := publicKey.Verify(sigBin, msgHash[:], nil); err != nil || !ok {
+		return nil, fmt.Errorf("generated signature verification failed")
+	}
+
+	var sig secp256k1ecdsa.Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return nil, fmt.Errorf("invalid signature format: %w", err)
+	}
+	pubX := publicKey.A.X.Bytes()
+	pubY := publicKey.A.Y.Bytes()
+
+	return &ProveInputEcdsa{
+		MsgHash: hex.EncodeToString(msgHash[:]),
+		R:       hex.EncodeToString(sig.R[:]),
+		S:       hex.EncodeToString(sig.S[:]),
+		PubX:    hex.EncodeToString(pubX[:]),
+		PubY:    hex.EncodeToString(pubY[:]),
+		Curve:   Secp256k1,
+	}, nil
+}
+
+// parseDERSignature parses an ASN.1 DER ECDSA signature into R and S.
+func parseDERSignature(der []byte) (r, s *big.Int, err error) {
+	var inner cryptobyte.String
+	r, s = &big.Int{}, &big.Int{}
+	input := cryptobyte.String(der)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return nil, nil, fmt.Errorf("invalid ASN.1 signature format")
+	}
+	return r, s, nil
+}
+
+// marshalDERSignature encodes R and S as an ASN.1 DER ECDSA signature.
+func marshalDERSignature(r, s *big.Int) ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(r)
+		b.AddASN1BigInt(s)
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding ASN.1 signature: %w", err)
+	}
+	return der, nil
+}
+
+// GenerateRandomInput is GenerateInput over a random 32-byte message.
+func GenerateRandomInput(curve Curve) (*ProveInputEcdsa, error) {
+	message := make([]byte, 32)
+	if _, err := rand.Read(message); err != nil {
+		return nil, fmt.Errorf("failed to generate random message: %w", err)
+	}
+	return GenerateInput(curve, message)
+}
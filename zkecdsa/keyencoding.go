@@ -0,0 +1,185 @@
+package zkecdsa
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// KeyEncoding selects how a proving key is written. Compressed keys have
+// their curve points compressed and checked when read, which costs seconds
+// for the largest keys; raw keys are about twice the size but are read
+// without any check, as trusted artifacts.
+type KeyEncoding string
+
+const (
+	KeyCompressed KeyEncoding = "compressed"
+	KeyRaw        KeyEncoding = "raw"
+)
+
+// ParseKeyEncoding parses a key encoding name. The empty string selects
+// KeyCompressed.
+func ParseKeyEncoding(name string) (KeyEncoding, error) {
+	switch strings.ToLower(name) {
+	case "", "compressed":
+		return KeyCompressed, nil
+	case "raw":
+		return KeyRaw, nil
+	default:
+		return "", fmt.Errorf("unsupported key encoding %q (supported: %s, %s)", name, KeyCompressed, KeyRaw)
+	}
+}
+
+// canonical returns the canonical name of e, KeyCompressed if e is unset, or
+// e unchanged if it is not a supported encoding.
+func (e KeyEncoding) canonical() KeyEncoding {
+	if parsed, err := ParseKeyEncoding(string(e)); err == nil {
+		return parsed
+	}
+	return e
+}
+
+// Proving keys written raw start with a header of keyHeaderLen bytes: the
+// magic, the encoding byte keyHeaderRaw and zero padding. Keys without the
+// header are compressed, as written by gnark itself, so they stay readable by
+// any gnark tool.
+const keyHeaderLen = 16
+
+var keyHeaderMagic = []byte("zkecdsak")
+
+// keyHeaderRaw is the encoding byte of the header of a raw key.
+const keyHeaderRaw byte = 2
+
+// WriteProvingKey writes pk to filename with the given encoding.
+func WriteProvingKey(filename string, pk ProvingKey, encoding KeyEncoding) error {
+	switch encoding.canonical() {
+	case KeyCompressed:
+		return WriteToFile(filename, pk)
+	case KeyRaw:
+		return WriteToFile(filename, rawKey{pk})
+	default:
+		return fmt.Errorf("unsupported key encoding %q", encoding)
+	}
+}
+
+// rawKey writes a proving key raw, after its header.
+type rawKey struct{ pk ProvingKey }
+
+func (k rawKey) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, keyHeaderLen)
+	copy(header, keyHeaderMagic)
+	header[len(keyHeaderMagic)] = keyHeaderRaw
+	buf := bufio.NewWriter(w)
+	n, err := buf.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := k.pk.WriteRawTo(buf)
+	if err != nil {
+		return int64(n) + m, err
+	}
+	return int64(n) + m, buf.Flush()
+}
+
+// ReadProvingKey reads a proving key of backend written by WriteProvingKey,
+// or by gnark, returning the encoding it was written with. A raw key is read
+// through a buffer without any check of its points.
+func ReadProvingKey(filename string, backend Backend) (ProvingKey, KeyEncoding, error) {
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		return nil, "", fmt.Errorf("%w: proving key not found at %s", ErrArtifactMissing, filename)
+	}
+	pk, err := backend.newProvingKey()
+	if err != nil {
+		return nil, "", err
+	}
+	encoding, err := readProvingKey(filename, pk, nil)
+	if err != nil {
+		return nil, "", corruptArtifact("proving key", err)
+	}
+	return pk, encoding, nil
+}
+
+// readProvingKey reads the proving key at filename into pk. If digest is not
+// nil, the whole file is written to it as it is read, so the key is hashed
+// without a second pass over it.
+func readProvingKey(filename string, pk ProvingKey, digest hash.Hash) (KeyEncoding, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var src io.Reader = file
+	if digest != nil {
+		src = io.TeeReader(file, digest)
+	}
+	r := bufio.NewReaderSize(src, 1<<20)
+	encoding, err := readKeyHeader(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading from file %s: %w", filename, err)
+	}
+	switch encoding {
+	case KeyCompressed:
+		if _, err := pk.ReadFrom(r); err != nil {
+			return "", fmt.Errorf("error reading from file %s into io.ReaderFrom: %w", filename, err)
+		}
+	case KeyRaw:
+		info, err := file.Stat()
+		if err != nil {
+			return "", fmt.Errorf("error reading from file %s: %w", filename, err)
+		}
+		keyLen := info.Size() - keyHeaderLen
+		n, err := pk.UnsafeReadFrom(r)
+		if err != nil {
+			return "", fmt.Errorf("error reading raw key from file %s: %w", filename, err)
+		}
+		if n != keyLen {
+			return "", fmt.Errorf("error reading raw key from file %s: %d trailing bytes", filename, keyLen-n)
+		}
+	}
+	if digest != nil {
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return "", fmt.Errorf("error reading from file %s: %w", filename, err)
+		}
+	}
+	return encoding, nil
+}
+
+// ProvingKeyEncoding returns the encoding of the proving key at filename from
+// its header, without reading the key.
+func ProvingKeyEncoding(filename string) (KeyEncoding, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("error opening file %s: %w", filename, err)
+	}
+	defer file.Close()
+	encoding, err := readKeyHeader(bufio.NewReaderSize(file, keyHeaderLen))
+	if err != nil {
+		return "", fmt.Errorf("error reading from file %s: %w", filename, err)
+	}
+	return encoding, nil
+}
+
+// readKeyHeader reads the header of a proving key, leaving r right after it,
+// or at the start of the key for a key without header.
+func readKeyHeader(r *bufio.Reader) (KeyEncoding, error) {
+	header, err := r.Peek(keyHeaderLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if len(header) < keyHeaderLen || !bytes.Equal(header[:len(keyHeaderMagic)], keyHeaderMagic) {
+		return KeyCompressed, nil
+	}
+	if encoding := header[len(keyHeaderMagic)]; encoding != keyHeaderRaw {
+		return "", fmt.Errorf("unsupported key encoding %d in the header", encoding)
+	}
+	if _, err := r.Discard(keyHeaderLen); err != nil {
+		return "", err
+	}
+	return KeyRaw, nil
+}
@@ -0,0 +1,186 @@
+package zkecdsa
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxMerkleDepth bounds the depth of key trees, the leaf index being an int.
+const maxMerkleDepth = 32
+
+// ParsePublicKeys parses a list of public keys on curve: PEM blocks, and hex
+// keys one per line. Blank lines and lines starting with # are skipped.
+func ParsePublicKeys(curve Curve, data []byte) ([]PublicKey, error) {
+	var keys []PublicKey
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		if len(data) == 0 {
+			return keys, nil
+		}
+		if bytes.HasPrefix(data, []byte("-----BEGIN")) {
+			block, rest := pem.Decode(data)
+			if block == nil {
+				return nil, fmt.Errorf("key %d: malformed PEM block", len(keys))
+			}
+			key, err := parsePEMPublicKey(curve, block)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %w", len(keys), err)
+			}
+			keys = append(keys, key)
+			data = rest
+			continue
+		}
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+		data = rest
+		if text := strings.TrimSpace(string(line)); !strings.HasPrefix(text, "#") {
+			key, err := ParsePublicKey(curve, text)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %w", len(keys), err)
+			}
+			keys = append(keys, key)
+		}
+	}
+}
+
+// KeyTree is a Merkle tree over the leaves of a list of public keys, see
+// KeyLeaf, padded with zero leaves to 2^depth. Only the nodes above actual
+// keys are stored.
+type KeyTree struct {
+	hash   FieldHash
+	curve  Curve
+	depth  int
+	keys   []PublicKey
+	levels [][]*big.Int // levels[0] holds the leaves, levels[depth] the root
+	zeros  []*big.Int   // zeros[i] is the root of an empty subtree of height i
+}
+
+// NewKeyTree builds the key tree of the given depth over keys on curve,
+// hashed with hash.
+func NewKeyTree(hash FieldHash, curve Curve, depth int, keys []PublicKey) (*KeyTree, error) {
+	if depth <= 0 || depth > maxMerkleDepth {
+		return nil, fmt.Errorf("invalid Merkle tree depth %d (supported: 1 to %d)", depth, maxMerkleDepth)
+	}
+	if len(keys) > 1<<depth {
+		return nil, fmt.Errorf("%d keys do not fit in a tree of depth %d", len(keys), depth)
+	}
+	t := &KeyTree{hash: hash.canonical(), curve: curve.canonical(), depth: depth, keys: keys}
+	leaves := make([]*big.Int, len(keys))
+	for i, key := range keys {
+		if err := key.check(curve); err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		leaf, err := KeyLeaf(hash, curve, key.X, key.Y)
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = leaf
+	}
+	t.zeros = []*big.Int{new(big.Int)}
+	t.levels = [][]*big.Int{leaves}
+	for level := 0; level < depth; level++ {
+		zero, err := hash.Sum(t.zeros[level], t.zeros[level])
+		if err != nil {
+			return nil, err
+		}
+		t.zeros = append(t.zeros, zero)
+
+		below := t.levels[level]
+		nodes := make([]*big.Int, (len(below)+1)/2)
+		for i := range nodes {
+			right := t.zeros[level]
+			if 2*i+1 < len(below) {
+				right = below[2*i+1]
+			}
+			if nodes[i], err = hash.Sum(below[2*i], right); err != nil {
+				return nil, err
+			}
+		}
+		t.levels = append(t.levels, nodes)
+	}
+	return t, nil
+}
+
+// Root returns the root of the tree.
+func (t *KeyTree) Root() *big.Int {
+	if root := t.levels[t.depth]; len(root) > 0 {
+		return root[0]
+	}
+	return t.zeros[t.depth]
+}
+
+// Depth returns the depth of the tree.
+func (t *KeyTree) Depth() int { return t.depth }
+
+// Keys returns the keys of the tree, in leaf order.
+func (t *KeyTree) Keys() []PublicKey { return t.keys }
+
+// IndexOf returns the leaf index of the key, or -1 if it is not in the tree.
+func (t *KeyTree) IndexOf(pubX, pubY *big.Int) int {
+	for i, key := range t.keys {
+		if key.X.Cmp(pubX) == 0 && key.Y.Cmp(pubY) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Path returns the siblings of the leaf at index, from the leaf up.
+func (t *KeyTree) Path(index int) ([]*big.Int, error) {
+	if index < 0 || index >= len(t.keys) {
+		return nil, fmt.Errorf("leaf index %d out of range, the tree has %d keys", index, len(t.keys))
+	}
+	path := make([]*big.Int, t.depth)
+	for level := range path {
+		sibling := index ^ 1
+		if nodes := t.levels[level]; sibling < len(nodes) {
+			path[level] = nodes[sibling]
+		} else {
+			path[level] = t.zeros[level]
+		}
+		index >>= 1
+	}
+	return path, nil
+}
+
+// MembershipInput returns the membership circuit witness input proving that
+// the signature of signed is by one of the keys of the tree.
+func (t *KeyTree) MembershipInput(signed *ProveInputEcdsa) (*MembershipInput, error) {
+	if signed.Curve != "" && signed.Curve.canonical() != t.curve {
+		return nil, fmt.Errorf("input is on curve %s but the key tree is for %s", signed.Curve, t.curve)
+	}
+	onCurve := *signed
+	onCurve.Curve = t.curve
+	d, err := onCurve.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	index := t.IndexOf(d.pubX, d.pubY)
+	if index < 0 {
+		return nil, fmt.Errorf("the signing key is not in the key tree")
+	}
+	path, err := t.Path(index)
+	if err != nil {
+		return nil, err
+	}
+	in := &MembershipInput{
+		ProveInputEcdsa: *signed,
+		Root:            fieldHex(t.Root()),
+		Index:           index,
+		TreeHash:        t.hash,
+	}
+	in.Curve = t.curve
+	for _, sibling := range path {
+		in.Path = append(in.Path, fieldHex(sibling))
+	}
+	return in, nil
+}
+
+// fieldHex returns the hex of a BN254 scalar field element as 32 big-endian
+// bytes.
+func fieldHex(e *big.Int) string {
+	return hex.EncodeToString(e.FillBytes(make([]byte, 32)))
+}
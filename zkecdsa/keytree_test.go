@@ -0,0 +1,168 @@
+package zkecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testKeys returns n random P-256 public keys.
+func testKeys(t *testing.T, n int) []PublicKey {
+	t.Helper()
+	keys := make([]PublicKey, n)
+	for i := range keys {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = PublicKey{X: priv.X, Y: priv.Y}
+	}
+	return keys
+}
+
+// rootFromPath hashes the leaf of key up its path, as MembershipCircuit does.
+func rootFromPath(t *testing.T, hash FieldHash, key PublicKey, index int, path []*big.Int) *big.Int {
+	t.Helper()
+	node, err := KeyLeaf(hash, P256, key.X, key.Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sibling := range path {
+		if index&1 == 0 {
+			node, err = hash.Sum(node, sibling)
+		} else {
+			node, err = hash.Sum(sibling, node)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		index >>= 1
+	}
+	return node
+}
+
+func TestKeyTreePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		hash  FieldHash
+		depth int
+		keys  int
+	}{
+		{"single key", MiMC, 1, 1},
+		{"full tree", MiMC, 2, 4},
+		{"padded tree", MiMC, 3, 5},
+		{"deep sparse tree", MiMC, 10, 3},
+		{"poseidon2", Poseidon2, 3, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := testKeys(t, tt.keys)
+			tree, err := NewKeyTree(tt.hash, P256, tt.depth, keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, key := range keys {
+				if index := tree.IndexOf(key.X, key.Y); index != i {
+					t.Errorf("IndexOf(key %d) = %d", i, index)
+				}
+				path, err := tree.Path(i)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(path) != tt.depth {
+					t.Fatalf("path of key %d has %d siblings, want %d", i, len(path), tt.depth)
+				}
+				if root := rootFromPath(t, tt.hash, key, i, path); root.Cmp(tree.Root()) != 0 {
+					t.Errorf("path of key %d leads to %x, want the root %x", i, root, tree.Root())
+				}
+			}
+		})
+	}
+}
+
+func TestKeyTreeEmptyRoot(t *testing.T) {
+	tree, err := NewKeyTree(MiMC, P256, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := new(big.Int)
+	level1, _ := MiMC.Sum(zero, zero)
+	root, _ := MiMC.Sum(level1, level1)
+	if tree.Root().Cmp(root) != 0 {
+		t.Errorf("Root() = %x, want the root of zero leaves %x", tree.Root(), root)
+	}
+}
+
+func TestKeyTreeErrors(t *testing.T) {
+	keys := testKeys(t, 3)
+	offCurve := []PublicKey{{X: big.NewInt(1), Y: big.NewInt(2)}}
+	tests := []struct {
+		name  string
+		depth int
+		keys  []PublicKey
+	}{
+		{"zero depth", 0, keys},
+		{"too deep", maxMerkleDepth + 1, keys},
+		{"too many keys", 1, keys},
+		{"key off the curve", 2, offCurve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyTree(MiMC, P256, tt.depth, tt.keys); err == nil {
+				t.Error("NewKeyTree succeeded")
+			}
+		})
+	}
+
+	tree, err := NewKeyTree(MiMC, P256, 2, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []int{-1, 3, 4} {
+		if _, err := tree.Path(index); err == nil {
+			t.Errorf("Path(%d) succeeded on a tree of 3 keys", index)
+		}
+	}
+	if other := testKeys(t, 1)[0]; tree.IndexOf(other.X, other.Y) != -1 {
+		t.Error("IndexOf found a key not in the tree")
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	keys := testKeys(t, 3)
+	der, err := x509.MarshalPKIXPublicKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: keys[1].X, Y: keys[1].Y})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := strings.Join([]string{
+		"# keys of the set",
+		"04" + hex.EncodeToString(keys[0].X.FillBytes(make([]byte, 32))) + hex.EncodeToString(keys[0].Y.FillBytes(make([]byte, 32))),
+		"",
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		"  " + hex.EncodeToString(elliptic.MarshalCompressed(elliptic.P256(), keys[2].X, keys[2].Y)) + "  ",
+	}, "\n")
+	parsed, err := ParsePublicKeys(P256, []byte(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(keys) {
+		t.Fatalf("parsed %d keys, want %d", len(parsed), len(keys))
+	}
+	for i := range keys {
+		if parsed[i].X.Cmp(keys[i].X) != 0 || parsed[i].Y.Cmp(keys[i].Y) != 0 {
+			t.Errorf("key %d parsed as (%x, %x), want (%x, %x)", i, parsed[i].X, parsed[i].Y, keys[i].X, keys[i].Y)
+		}
+	}
+
+	for _, bad := range []string{"zz", "-----BEGIN PUBLIC KEY-----\nnot base64\n"} {
+		if _, err := ParsePublicKeys(P256, []byte(bad)); err == nil {
+			t.Errorf("ParsePublicKeys(%q) succeeded", bad)
+		}
+	}
+}
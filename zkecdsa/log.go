@@ -0,0 +1,74 @@
+package zkecdsa
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+
+	gnarklogger "github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
+)
+
+// logger receives the structured logs of the package, see SetLogger.
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	logger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger sends the logs of the package to l: artifact loads at info
+// level, each proof and verification at debug level and failures at info
+// level when the input or proof is at fault, else at warn level, with their
+// timings and ErrorKind as attributes. The progress logs
+// of gnark are routed to l as well, at their own level. A nil l discards
+// both, which is the default.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		logger.Store(slog.New(slog.DiscardHandler))
+		gnarklogger.Disable()
+		return
+	}
+	logger.Store(l)
+	gnarklogger.Set(zerolog.New(gnarkWriter{l}))
+}
+
+// Logger returns the logger set by SetLogger.
+func Logger() *slog.Logger {
+	return logger.Load()
+}
+
+// gnarkWriter forwards the JSON log lines of gnark's zerolog logger to an
+// slog logger.
+type gnarkWriter struct {
+	l *slog.Logger
+}
+
+func (w gnarkWriter) Write(p []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	for scanner.Scan() {
+		var fields map[string]interface{}
+		if json.Unmarshal(scanner.Bytes(), &fields) != nil {
+			w.l.Debug(string(scanner.Bytes()), "component", "gnark")
+			continue
+		}
+		message, _ := fields[zerolog.MessageFieldName].(string)
+		levelName, _ := fields[zerolog.LevelFieldName].(string)
+		delete(fields, zerolog.MessageFieldName)
+		delete(fields, zerolog.LevelFieldName)
+		level := slog.LevelDebug
+		if zl, err := zerolog.ParseLevel(levelName); err == nil && zl >= zerolog.WarnLevel {
+			level = slog.LevelWarn
+		} else if zl == zerolog.InfoLevel {
+			level = slog.LevelInfo
+		}
+		attrs := []slog.Attr{slog.String("component", "gnark")}
+		for key, value := range fields {
+			attrs = append(attrs, slog.Any(key, value))
+		}
+		w.l.LogAttrs(context.Background(), level, message, attrs...)
+	}
+	return len(p), nil
+}
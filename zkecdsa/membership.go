@@ -0,0 +1,93 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// DefaultMerkleDepth is the depth of the key tree of the membership circuit
+// when none is configured, room for 65536 keys.
+const DefaultMerkleDepth = 16
+
+// MembershipCircuit verifies an ECDSA signature over the public message hash
+// Msg under a private key whose leaf, see KeyLeaf, is in the Merkle tree of
+// public Root. The proof reveals the key set but not which key signed. Path
+// holds the siblings from the leaf up, the bits of Index telling whether the
+// node is a left (0) or right (1) child at each level.
+type MembershipCircuit[T, S emulated.FieldParams] struct {
+	Sig   ecdsa.Signature[S]
+	Msg   emulated.Element[S] `gnark:",public"`
+	Pub   ecdsa.PublicKey[T, S]
+	Path  []frontend.Variable
+	Index frontend.Variable
+	Root  frontend.Variable `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *MembershipCircuit[T, S]) Define(api frontend.API) error {
+	signature := EcdsaCircuit[T, S]{Sig: c.Sig, Msg: c.Msg, Pub: c.Pub}
+	if err := signature.Define(api); err != nil {
+		return err
+	}
+	hasher, err := c.hash.newHasher(api)
+	if err != nil {
+		return err
+	}
+	// The limbs are not reduced, but the leaves of the key tree are hashed
+	// from canonical values, which a non-canonical representation of the key
+	// would not match
+	hasher.Write(c.Pub.X.Limbs...)
+	hasher.Write(c.Pub.Y.Limbs...)
+	node := hasher.Sum()
+
+	bits := api.ToBinary(c.Index, len(c.Path))
+	for i, sibling := range c.Path {
+		hasher.Reset()
+		hasher.Write(api.Select(bits[i], sibling, node), api.Select(bits[i], node, sibling))
+		node = hasher.Sum()
+	}
+	api.AssertIsEqual(node, c.Root)
+	return nil
+}
+
+// KeyLeaf computes off-circuit the leaf of a public key in the key tree of
+// MembershipCircuit: the hash of the limbs of X then Y in their emulated
+// field, as for BatchCommitment.
+func KeyLeaf(hash FieldHash, curve Curve, pubX, pubY *big.Int) (*big.Int, error) {
+	switch curve.canonical() {
+	case P256:
+		return keyLeaf[emulated.P256Fp](hash, pubX, pubY)
+	case Secp256k1:
+		return keyLeaf[emulated.Secp256k1Fp](hash, pubX, pubY)
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func keyLeaf[T emulated.FieldParams](hash FieldHash, pubX, pubY *big.Int) (*big.Int, error) {
+	return hash.Sum(append(limbs[T](pubX), limbs[T](pubY)...)...)
+}
+
+// NewMembershipCircuit returns the empty membership circuit definition to
+// compile for curve, over key trees of the given depth hashed with hash.
+func NewMembershipCircuit(curve Curve, depth int, hash FieldHash) (frontend.Circuit, error) {
+	if depth <= 0 || depth > maxMerkleDepth {
+		return nil, fmt.Errorf("invalid Merkle tree depth %d (supported: 1 to %d)", depth, maxMerkleDepth)
+	}
+	if _, err := ParseFieldHash(string(hash)); err != nil {
+		return nil, err
+	}
+	switch curve.canonical() {
+	case P256:
+		return &MembershipCircuit[emulated.P256Fp, emulated.P256Fr]{Path: make([]frontend.Variable, depth), hash: hash.canonical()}, nil
+	case Secp256k1:
+		return &MembershipCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{Path: make([]frontend.Variable, depth), hash: hash.canonical()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
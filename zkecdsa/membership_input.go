@@ -0,0 +1,205 @@
+package zkecdsa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// MembershipInput struct for JSON serialization of membership circuit
+// witness inputs: the signature input extended with the Merkle path of the
+// signing key in the key tree, see KeyTree.MembershipInput.
+type MembershipInput struct {
+	ProveInputEcdsa
+
+	Root  string   `json:"root"`  // Hex string of the key tree root
+	Path  []string `json:"path"`  // Hex strings of the siblings, from the leaf up
+	Index int      `json:"index"` // Leaf index of the signing key
+
+	// Filled in from the artifacts by Prover if unset
+	TreeHash FieldHash `json:"treeHash,omitempty"` // Hash of the key tree, MiMC if empty
+}
+
+// Circuit returns CircuitMembership.
+func (in *MembershipInput) Circuit() CircuitType { return CircuitMembership }
+
+func (in *MembershipInput) curve() Curve { return in.Curve }
+
+func (in *MembershipInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// forCircuit returns in with the tree hash of the membership circuit
+// compiled for trees of the given depth over hash, checking the path length.
+// A hash already set in in must match.
+func (in *MembershipInput) forCircuit(depth int, hash FieldHash) (*MembershipInput, error) {
+	if len(in.Path) != depth {
+		return nil, fmt.Errorf("Merkle path of %d nodes but the prover was loaded for trees of depth %d", len(in.Path), depth)
+	}
+	shaped := *in
+	if shaped.TreeHash == "" {
+		shaped.TreeHash = hash.canonical()
+	} else if shaped.TreeHash.canonical() != hash.canonical() {
+		return nil, fmt.Errorf("input tree is hashed with %s but the prover was loaded for %s", shaped.TreeHash, hash.canonical())
+	}
+	return &shaped, nil
+}
+
+// decodedMembership holds the values of a MembershipInput once the hex is
+// decoded.
+type decodedMembership struct {
+	*decodedInput
+	root *big.Int
+	path []*big.Int
+}
+
+// decodePublic decodes the message hash and the root, which the membership
+// circuit exposes.
+func (in *MembershipInput) decodePublic() (*decodedMembership, error) {
+	msgHashBytes, err := decodeFixed("MsgHash", in.MsgHash, MsgHashLen)
+	if err != nil {
+		return nil, err
+	}
+	rootBytes, err := decodeHex("Root", in.Root)
+	if err != nil {
+		return nil, err
+	}
+	return &decodedMembership{
+		decodedInput: &decodedInput{msgHash: msgHashBytes},
+		root:         new(big.Int).SetBytes(rootBytes),
+	}, nil
+}
+
+// decode decodes every field of the input.
+func (in *MembershipInput) decode() (*decodedMembership, error) {
+	d, err := in.ProveInputEcdsa.decode()
+	if err != nil {
+		return nil, err
+	}
+	rootBytes, err := decodeHex("Root", in.Root)
+	if err != nil {
+		return nil, err
+	}
+	if in.Index < 0 || in.Index >= 1<<len(in.Path) {
+		return nil, fmt.Errorf("leaf index %d out of range for a Merkle path of %d nodes", in.Index, len(in.Path))
+	}
+	m := &decodedMembership{decodedInput: d, root: new(big.Int).SetBytes(rootBytes)}
+	for i, sibling := range in.Path {
+		siblingBytes, err := decodeHex(fmt.Sprintf("Path[%d]", i), sibling)
+		if err != nil {
+			return nil, err
+		}
+		m.path = append(m.path, new(big.Int).SetBytes(siblingBytes))
+	}
+	return m, nil
+}
+
+// Assignment builds the full MembershipCircuit assignment over the input
+// curve. publicInputs is ignored, the message hash and root are always public.
+func (in *MembershipInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return membershipAssignment[emulated.P256Fp, emulated.P256Fr](in.Index, d), nil
+	case Secp256k1:
+		return membershipAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](in.Index, d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the MembershipCircuit assignment from the message
+// hash and the root alone, for trees of depth len(Path). The key, signature
+// and path values are ignored.
+func (in *MembershipInput) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return membershipPublicAssignment[emulated.P256Fp, emulated.P256Fr](len(in.Path), d), nil
+	case Secp256k1:
+		return membershipPublicAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](len(in.Path), d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func membershipAssignment[T, S emulated.FieldParams](index int, d *decodedMembership) frontend.Circuit {
+	path := make([]frontend.Variable, len(d.path))
+	for i, sibling := range d.path {
+		path[i] = sibling
+	}
+	return &MembershipCircuit[T, S]{
+		Sig: ecdsa.Signature[S]{
+			R: emulated.ValueOf[S](d.r),
+			S: emulated.ValueOf[S](d.s),
+		},
+		Msg: emulated.ValueOf[S](d.msgHash),
+		Pub: ecdsa.PublicKey[T, S]{
+			X: emulated.ValueOf[T](d.pubX),
+			Y: emulated.ValueOf[T](d.pubY),
+		},
+		Path:  path,
+		Index: index,
+		Root:  d.root,
+	}
+}
+
+func membershipPublicAssignment[T, S emulated.FieldParams](depth int, d *decodedMembership) frontend.Circuit {
+	return &MembershipCircuit[T, S]{
+		Msg:  emulated.ValueOf[S](d.msgHash),
+		Path: make([]frontend.Variable, depth),
+		Root: d.root,
+	}
+}
+
+// GenerateMembershipInput signs sha256(msg) with a fresh key on curve and
+// returns the membership circuit witness input for a key tree of the given
+// depth, hashed with hash, holding the signing key among other fresh keys.
+func GenerateMembershipInput(curve Curve, msg []byte, depth int, hash FieldHash) (*MembershipInput, error) {
+	signed, err := GenerateInput(curve, msg)
+	if err != nil {
+		return nil, err
+	}
+	d, err := signed.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	nbKeys := min(8, 1<<depth)
+	position, err := rand.Int(rand.Reader, big.NewInt(int64(nbKeys)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to draw the key position: %w", err)
+	}
+	keys := make([]PublicKey, nbKeys)
+	for i := range keys {
+		if i == int(position.Int64()) {
+			keys[i] = PublicKey{X: d.pubX, Y: d.pubY}
+			continue
+		}
+		other, err := GenerateInput(curve, msg)
+		if err != nil {
+			return nil, err
+		}
+		od, err := other.decodePublic()
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = PublicKey{X: od.pubX, Y: od.pubY}
+	}
+	tree, err := NewKeyTree(hash, curve, depth, keys)
+	if err != nil {
+		return nil, err
+	}
+	return tree.MembershipInput(signed)
+}
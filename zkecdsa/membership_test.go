@@ -0,0 +1,81 @@
+package zkecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type p256Membership = MembershipCircuit[emulated.P256Fp, emulated.P256Fr]
+
+func TestMembershipCircuit(t *testing.T) {
+	const depth, index = 3, 2
+	signed, err := GenerateInput(P256, []byte("membership"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubX, _ := new(big.Int).SetString(signed.PubX, 16)
+	pubY, _ := new(big.Int).SetString(signed.PubY, 16)
+	keys := testKeys(t, 5)
+	keys[index] = PublicKey{X: pubX, Y: pubY}
+
+	for _, hash := range FieldHashes {
+		t.Run(string(hash), func(t *testing.T) {
+			tree, err := NewKeyTree(hash, P256, depth, keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The same keys but the signer's
+			otherTree, err := NewKeyTree(hash, P256, depth, append(append([]PublicKey(nil), keys[:index]...), keys[index+1:]...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			circuit, err := NewMembershipCircuit(P256, depth, hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tests := []struct {
+				name  string
+				edit  func(c *p256Membership)
+				valid bool
+			}{
+				{"key tree root", func(c *p256Membership) {}, true},
+				{"wrong sibling", func(c *p256Membership) {
+					c.Path[1] = new(big.Int).Add(c.Path[1].(*big.Int), big.NewInt(1))
+				}, false},
+				{"wrong index", func(c *p256Membership) { c.Index = index + 1 }, false},
+				{"index past the tree", func(c *p256Membership) { c.Index = index + 1<<depth }, false},
+				{"root of a tree without the key", func(c *p256Membership) { c.Root = otherTree.Root() }, false},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					in, err := tree.MembershipInput(signed)
+					if err != nil {
+						t.Fatal(err)
+					}
+					assignment, err := in.Assignment(true)
+					if err != nil {
+						t.Fatal(err)
+					}
+					c := assignment.(*p256Membership)
+					if c.Index != index {
+						t.Fatalf("signing key at index %v, want %d", c.Index, index)
+					}
+					// The root a relying party takes from the key tree
+					c.Root = tree.Root()
+					tt.edit(c)
+					err = test.IsSolved(circuit, c, ecc.BN254.ScalarField())
+					if tt.valid && err != nil {
+						t.Fatalf("IsSolved = %v", err)
+					}
+					if !tt.valid && err == nil {
+						t.Fatal("IsSolved succeeded")
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// DefaultMaxMessageLen is the message capacity of the message circuits when
+// none is configured.
+const DefaultMaxMessageLen = 128
+
+// messageChunkLen is the number of message bytes packed into one field
+// element of a message commitment.
+const messageChunkLen = 31
+
+// MessageCircuit verifies an ECDSA signature over sha256(Message), the hash
+// being computed in-circuit over the first MessageLen bytes. Every input is
+// private. Message has the capacity the circuit was compiled for and is zero
+// padded beyond MessageLen.
+type MessageCircuit[T, S emulated.FieldParams] struct {
+	Message    []uints.U8
+	MessageLen frontend.Variable
+	Sig        ecdsa.Signature[S]
+	Pub        ecdsa.PublicKey[T, S]
+}
+
+func (c *MessageCircuit[T, S]) Define(api frontend.API) error {
+	return verifyMessage(api, c.Message, c.MessageLen, &c.Sig, &c.Pub)
+}
+
+// MessagePublicCircuit is the variant of MessageCircuit where the message,
+// its length and the public key are public inputs.
+type MessagePublicCircuit[T, S emulated.FieldParams] struct {
+	Message    []uints.U8        `gnark:",public"`
+	MessageLen frontend.Variable `gnark:",public"`
+	Sig        ecdsa.Signature[S]
+	Pub        ecdsa.PublicKey[T, S] `gnark:",public"`
+}
+
+func (c *MessagePublicCircuit[T, S]) Define(api frontend.API) error {
+	return verifyMessage(api, c.Message, c.MessageLen, &c.Sig, &c.Pub)
+}
+
+// MessageCommitmentCircuit is the variant of MessageCircuit where the message
+// stays private but is bound to the public Commitment, see MessageCommitment.
+// The public key is public.
+type MessageCommitmentCircuit[T, S emulated.FieldParams] struct {
+	Message    []uints.U8
+	MessageLen frontend.Variable
+	Salt       frontend.Variable // Blinds the commitment of low-entropy messages
+	Sig        ecdsa.Signature[S]
+	Commitment frontend.Variable     `gnark:",public"`
+	Pub        ecdsa.PublicKey[T, S] `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *MessageCommitmentCircuit[T, S]) Define(api frontend.API) error {
+	if err := verifyMessage(api, c.Message, c.MessageLen, &c.Sig, &c.Pub); err != nil {
+		return err
+	}
+	hasher, err := c.hash.newHasher(api)
+	if err != nil {
+		return err
+	}
+	hasher.Write(c.Salt, c.MessageLen)
+	for start := 0; start < len(c.Message); start += messageChunkLen {
+		end := min(start+messageChunkLen, len(c.Message))
+		var chunk frontend.Variable = 0
+		for _, b := range c.Message[start:end] {
+			chunk = api.Add(api.Mul(chunk, 256), b.Val)
+		}
+		hasher.Write(chunk)
+	}
+	api.AssertIsEqual(hasher.Sum(), c.Commitment)
+	return nil
+}
+
+// MessageCommitment computes off-circuit the commitment checked by
+// MessageCommitmentCircuit: hash(salt, len(message), chunks...), the message
+// being zero padded to maxLen and packed big-endian into 31-byte chunks.
+func MessageCommitment(hash FieldHash, message []byte, maxLen int, salt *big.Int) (*big.Int, error) {
+	if len(message) > maxLen {
+		return nil, fmt.Errorf("message is %d bytes, at most %d are supported", len(message), maxLen)
+	}
+	padded := make([]byte, maxLen)
+	copy(padded, message)
+	elements := []*big.Int{salt, big.NewInt(int64(len(message)))}
+	for start := 0; start < maxLen; start += messageChunkLen {
+		end := min(start+messageChunkLen, maxLen)
+		elements = append(elements, new(big.Int).SetBytes(padded[start:end]))
+	}
+	return hash.Sum(elements...)
+}
+
+// verifyMessage hashes the first msgLen bytes of msg and verifies sig over
+// the hash with the EcdsaCircuit logic. The bytes past msgLen must be zero.
+func verifyMessage[T, S emulated.FieldParams](api frontend.API, msg []uints.U8, msgLen frontend.Variable, sig *ecdsa.Signature[S], pub *ecdsa.PublicKey[T, S]) error {
+	bf, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsLessOrEqual(msgLen, len(msg))
+	comparator := cmp.NewBoundedComparator(api, big.NewInt(int64(len(msg)+1)), false)
+	for i := range msg {
+		// Range check the bytes, the hash gadget takes them as given
+		msg[i] = bf.ByteValueOf(msg[i].Val)
+		padding := api.Sub(1, comparator.IsLess(i, msgLen))
+		api.AssertIsEqual(api.Mul(padding, msg[i].Val), 0)
+	}
+
+	hasher, err := sha2.New(api)
+	if err != nil {
+		return err
+	}
+	hasher.Write(msg)
+	digest := hasher.FixedLengthSum(msgLen)
+
+	scalars, err := emulated.NewField[S](api)
+	if err != nil {
+		return err
+	}
+	// The digest is big-endian, FromBits takes the least significant bit first
+	bits := make([]frontend.Variable, 0, 8*len(digest))
+	for i := len(digest) - 1; i >= 0; i-- {
+		bits = append(bits, api.ToBinary(digest[i].Val, 8)...)
+	}
+	signature := EcdsaCircuit[T, S]{Sig: *sig, Msg: *scalars.FromBits(bits...), Pub: *pub}
+	return signature.Define(api)
+}
+
+// NewMessageCircuit returns the empty message circuit definition to compile
+// for curve, with room for maxLen message bytes. commit selects
+// MessageCommitmentCircuit over hash, otherwise publicInputs selects
+// MessagePublicCircuit over MessageCircuit.
+func NewMessageCircuit(curve Curve, maxLen int, publicInputs, commit bool, hash FieldHash) (frontend.Circuit, error) {
+	if maxLen <= 0 {
+		return nil, fmt.Errorf("invalid maximum message length %d", maxLen)
+	}
+	if commit {
+		if _, err := ParseFieldHash(string(hash)); err != nil {
+			return nil, err
+		}
+	}
+	switch curve.canonical() {
+	case P256:
+		return newMessageCircuit[emulated.P256Fp, emulated.P256Fr](maxLen, publicInputs, commit, hash.canonical()), nil
+	case Secp256k1:
+		return newMessageCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](maxLen, publicInputs, commit, hash.canonical()), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func newMessageCircuit[T, S emulated.FieldParams](maxLen int, publicInputs, commit bool, hash FieldHash) frontend.Circuit {
+	switch {
+	case commit:
+		return &MessageCommitmentCircuit[T, S]{Message: make([]uints.U8, maxLen), hash: hash}
+	case publicInputs:
+		return &MessagePublicCircuit[T, S]{Message: make([]uints.U8, maxLen)}
+	default:
+		return &MessageCircuit[T, S]{Message: make([]uints.U8, maxLen)}
+	}
+}
@@ -0,0 +1,281 @@
+package zkecdsa
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// MessageInput struct for JSON serialization of message circuit witness
+// inputs. The signature is over sha256(Message).
+type MessageInput struct {
+	Message string `json:"message,omitempty"` // Hex string of the message
+	R       string `json:"r,omitempty"`       // Hex string of signature R
+	S       string `json:"s,omitempty"`       // Hex string of signature S
+	PubX    string `json:"pubX"`              // Hex string of public key X
+	PubY    string `json:"pubY"`              // Hex string of public key Y
+	Curve   Curve  `json:"curve,omitempty"`   // Signature curve, P256 if empty
+
+	// Shape of the circuit, filled in from the artifacts by Prover if unset
+	MaxMessageLen  int       `json:"maxMessageLen,omitempty"`  // Message capacity, DefaultMaxMessageLen if zero
+	CommitmentHash FieldHash `json:"commitmentHash,omitempty"` // Commitment variant only: hash of the commitment
+
+	// Commitment variant only
+	Salt       string `json:"salt,omitempty"`       // Hex string of the commitment salt, zero if empty
+	Commitment string `json:"commitment,omitempty"` // Hex string of the commitment, computed from Message if empty
+}
+
+// Circuit returns CircuitMessage.
+func (in *MessageInput) Circuit() CircuitType { return CircuitMessage }
+
+func (in *MessageInput) curve() Curve { return in.Curve }
+
+func (in *MessageInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// forCircuit returns in with the shape of the message circuit compiled with
+// maxLen bytes and, if commit, a commitment over hash. A shape already set in
+// in must match.
+func (in *MessageInput) forCircuit(maxLen int, commit bool, hash FieldHash) (*MessageInput, error) {
+	shaped := *in
+	if shaped.MaxMessageLen == 0 {
+		shaped.MaxMessageLen = maxLen
+	} else if shaped.MaxMessageLen != maxLen {
+		return nil, fmt.Errorf("input is for messages of at most %d bytes but the prover was loaded for %d", shaped.MaxMessageLen, maxLen)
+	}
+	switch {
+	case !commit && shaped.CommitmentHash != "":
+		return nil, fmt.Errorf("input commits to the message but the prover was loaded without commitment")
+	case commit && shaped.CommitmentHash == "":
+		shaped.CommitmentHash = hash.canonical()
+	case commit && shaped.CommitmentHash.canonical() != hash.canonical():
+		return nil, fmt.Errorf("input commits with %s but the prover was loaded for %s", shaped.CommitmentHash, hash.canonical())
+	}
+	return &shaped, nil
+}
+
+// maxLen returns the message capacity of the input.
+func (in *MessageInput) maxLen() int {
+	if in.MaxMessageLen == 0 {
+		return DefaultMaxMessageLen
+	}
+	return in.MaxMessageLen
+}
+
+// decodedMessage holds the values of a MessageInput once the hex is decoded.
+type decodedMessage struct {
+	message    []byte
+	salt       *big.Int
+	commitment *big.Int
+	r, s       *big.Int
+	pubX, pubY *big.Int
+}
+
+// decodePublic decodes the public key, and either the message or the
+// commitment depending on the variant.
+func (in *MessageInput) decodePublic() (*decodedMessage, error) {
+	pubX, pubY, err := decodePoint(in.Curve, "", in.PubX, in.PubY)
+	if err != nil {
+		return nil, err
+	}
+	d := &decodedMessage{pubX: pubX, pubY: pubY}
+	if in.CommitmentHash != "" && in.Commitment != "" {
+		commitmentBytes, err := decodeHex("Commitment", in.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		d.commitment = new(big.Int).SetBytes(commitmentBytes)
+		return d, nil
+	}
+	if err := in.decodeMessage(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// decodeMessage decodes the message, and the salt and commitment of the
+// commitment variant, into d.
+func (in *MessageInput) decodeMessage(d *decodedMessage) error {
+	message, err := decodeHex("Message", in.Message)
+	if err != nil {
+		return err
+	}
+	if len(message) > in.maxLen() {
+		return fmt.Errorf("message is %d bytes, at most %d are supported", len(message), in.maxLen())
+	}
+	d.message = message
+	if in.CommitmentHash == "" {
+		return nil
+	}
+	saltBytes, err := decodeHex("Salt", in.Salt)
+	if err != nil {
+		return err
+	}
+	d.salt = new(big.Int).SetBytes(saltBytes)
+	commitment, err := MessageCommitment(in.CommitmentHash, message, in.maxLen(), d.salt)
+	if err != nil {
+		return err
+	}
+	if d.commitment != nil && d.commitment.Cmp(commitment) != 0 {
+		return fmt.Errorf("message and salt do not match the commitment")
+	}
+	d.commitment = commitment
+	return nil
+}
+
+// decode decodes every field of the input.
+func (in *MessageInput) decode() (*decodedMessage, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	if d.message == nil {
+		if err := in.decodeMessage(d); err != nil {
+			return nil, err
+		}
+	}
+	if d.r, err = decodeScalar(in.Curve, "R", in.R); err != nil {
+		return nil, err
+	}
+	if d.s, err = decodeScalar(in.Curve, "S", in.S); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (in *MessageInput) signatureS() ([]*big.Int, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	return []*big.Int{d.s}, nil
+}
+
+// Assignment builds the full witness assignment for the selected message
+// circuit variant over the input curve. A set CommitmentHash selects
+// MessageCommitmentCircuit regardless of publicInputs.
+func (in *MessageInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return messageAssignment[emulated.P256Fp, emulated.P256Fr](in.maxLen(), publicInputs, in.CommitmentHash != "", d), nil
+	case Secp256k1:
+		return messageAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](in.maxLen(), publicInputs, in.CommitmentHash != "", d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the assignment of the public message circuit
+// variants from the public key and the message, or the commitment when
+// CommitmentHash is set. R, S and, given a commitment, the message are ignored.
+func (in *MessageInput) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return messagePublicAssignment[emulated.P256Fp, emulated.P256Fr](in.maxLen(), in.CommitmentHash != "", d), nil
+	case Secp256k1:
+		return messagePublicAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](in.maxLen(), in.CommitmentHash != "", d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func messageBytes(message []byte, maxLen int) []uints.U8 {
+	padded := make([]uints.U8, maxLen)
+	for i := range padded {
+		padded[i] = uints.NewU8(0)
+	}
+	for i, b := range message {
+		padded[i] = uints.NewU8(b)
+	}
+	return padded
+}
+
+func messageAssignment[T, S emulated.FieldParams](maxLen int, publicInputs, commit bool, d *decodedMessage) frontend.Circuit {
+	message := messageBytes(d.message, maxLen)
+	sig := ecdsa.Signature[S]{
+		R: emulated.ValueOf[S](d.r),
+		S: emulated.ValueOf[S](d.s),
+	}
+	pub := ecdsa.PublicKey[T, S]{
+		X: emulated.ValueOf[T](d.pubX),
+		Y: emulated.ValueOf[T](d.pubY),
+	}
+	switch {
+	case commit:
+		return &MessageCommitmentCircuit[T, S]{
+			Message:    message,
+			MessageLen: len(d.message),
+			Salt:       d.salt,
+			Sig:        sig,
+			Commitment: d.commitment,
+			Pub:        pub,
+		}
+	case publicInputs:
+		return &MessagePublicCircuit[T, S]{Message: message, MessageLen: len(d.message), Sig: sig, Pub: pub}
+	default:
+		return &MessageCircuit[T, S]{Message: message, MessageLen: len(d.message), Sig: sig, Pub: pub}
+	}
+}
+
+func messagePublicAssignment[T, S emulated.FieldParams](maxLen int, commit bool, d *decodedMessage) frontend.Circuit {
+	pub := ecdsa.PublicKey[T, S]{
+		X: emulated.ValueOf[T](d.pubX),
+		Y: emulated.ValueOf[T](d.pubY),
+	}
+	if commit {
+		return &MessageCommitmentCircuit[T, S]{Message: make([]uints.U8, maxLen), Commitment: d.commitment, Pub: pub}
+	}
+	return &MessagePublicCircuit[T, S]{Message: messageBytes(d.message, maxLen), MessageLen: len(d.message), Pub: pub}
+}
+
+// GenerateMessageInput signs sha256(msg) with a fresh key on curve and returns
+// the message circuit witness input for messages of at most maxLen bytes. If
+// commit, the message is committed to over hash with a random salt.
+func GenerateMessageInput(curve Curve, msg []byte, maxLen int, commit bool, hash FieldHash) (*MessageInput, error) {
+	signed, err := GenerateInput(curve, msg)
+	if err != nil {
+		return nil, err
+	}
+	in := &MessageInput{
+		Message:       hex.EncodeToString(msg),
+		R:             signed.R,
+		S:             signed.S,
+		PubX:          signed.PubX,
+		PubY:          signed.PubY,
+		Curve:         signed.Curve,
+		MaxMessageLen: maxLen,
+	}
+	if !commit {
+		return in, nil
+	}
+	var salt fr.Element
+	if _, err := salt.SetRandom(); err != nil {
+		return nil, fmt.Errorf("failed to generate random salt: %w", err)
+	}
+	saltBytes := salt.Bytes()
+	in.CommitmentHash = hash.canonical()
+	in.Salt = hex.EncodeToString(saltBytes[:])
+	commitment, err := MessageCommitment(in.CommitmentHash, msg, in.maxLen(), salt.BigInt(new(big.Int)))
+	if err != nil {
+		return nil, err
+	}
+	in.Commitment = hex.EncodeToString(commitment.Bytes())
+	return in, nil
+}
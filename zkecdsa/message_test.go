@@ -0,0 +1,120 @@
+package zkecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type (
+	p256MessagePublic     = MessagePublicCircuit[emulated.P256Fp, emulated.P256Fr]
+	p256MessageCommitment = MessageCommitmentCircuit[emulated.P256Fp, emulated.P256Fr]
+)
+
+// testMessageLen is the message capacity of the tested circuits, enough for
+// two SHA-256 blocks.
+const testMessageLen = 64
+
+// testMessageAssignment returns the P-256 message circuit and the
+// assignment of a signature over message, in the variant selected by
+// publicInputs and commit.
+func testMessageAssignment(t *testing.T, message []byte, publicInputs, commit bool) (circuit, assignment frontend.Circuit) {
+	t.Helper()
+	in, err := GenerateMessageInput(P256, message, testMessageLen, commit, MiMC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if circuit, err = NewMessageCircuit(P256, testMessageLen, publicInputs, commit, MiMC); err != nil {
+		t.Fatal(err)
+	}
+	if assignment, err = in.Assignment(publicInputs); err != nil {
+		t.Fatal(err)
+	}
+	return circuit, assignment
+}
+
+func TestMessagePublicCircuit(t *testing.T) {
+	message := []byte("transfer 100 to alice")
+	tests := []struct {
+		name    string
+		message []byte
+		edit    func(c *p256MessagePublic)
+		valid   bool
+	}{
+		{"signed message", message, func(c *p256MessagePublic) {}, true},
+		{"empty message", nil, func(c *p256MessagePublic) {}, true},
+		{"message of the full capacity", make([]byte, testMessageLen), func(c *p256MessagePublic) {}, true},
+		{"altered message", message, func(c *p256MessagePublic) {
+			c.Message[9] = uints.NewU8('9')
+		}, false},
+		{"truncated message", message, func(c *p256MessagePublic) {
+			c.MessageLen = len(message) - 1
+			c.Message[len(message)-1] = uints.NewU8(0)
+		}, false},
+		{"byte past the message length", message, func(c *p256MessagePublic) {
+			c.Message[len(message)] = uints.NewU8('!')
+		}, false},
+		{"length past the capacity", make([]byte, testMessageLen), func(c *p256MessagePublic) {
+			c.MessageLen = testMessageLen + 1
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, assignment := testMessageAssignment(t, tt.message, true, false)
+			tt.edit(assignment.(*p256MessagePublic))
+			err := test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
+
+func TestMessageCommitmentCircuit(t *testing.T) {
+	message := []byte("ballot: option 2")
+	tests := []struct {
+		name  string
+		edit  func(t *testing.T, c *p256MessageCommitment)
+		valid bool
+	}{
+		{"committed message", func(t *testing.T, c *p256MessageCommitment) {}, true},
+		{"commitment of another message", func(t *testing.T, c *p256MessageCommitment) {
+			commitment, err := MessageCommitment(MiMC, []byte("ballot: option 1"), testMessageLen, c.Salt.(*big.Int))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Commitment = commitment
+		}, false},
+		{"commitment with another salt", func(t *testing.T, c *p256MessageCommitment) {
+			c.Salt = new(big.Int).Add(c.Salt.(*big.Int), big.NewInt(1))
+		}, false},
+		{"commitment of another length", func(t *testing.T, c *p256MessageCommitment) {
+			commitment, err := MessageCommitment(MiMC, append(append([]byte(nil), message...), 0), testMessageLen, c.Salt.(*big.Int))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Commitment = commitment
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, assignment := testMessageAssignment(t, message, false, true)
+			tt.edit(t, assignment.(*p256MessageCommitment))
+			err := test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package zkecdsa
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics holds the collectors registered by EnableMetrics, nil until then.
+var metrics atomic.Pointer[metricSet]
+
+type metricSet struct {
+	prove    *prometheus.HistogramVec
+	verify   *prometheus.HistogramVec
+	load     *prometheus.HistogramVec
+	failures *prometheus.CounterVec
+	inFlight prometheus.Gauge
+}
+
+// EnableMetrics registers the metrics of the package with reg and starts
+// recording them. Until it is called no metric is recorded. The metrics are:
+//
+//	zkecdsa_prove_duration_seconds{circuit,curve,backend}   histogram of successful proofs
+//	zkecdsa_verify_duration_seconds{backend}                histogram of verifications, failed ones included
+//	zkecdsa_artifact_load_duration_seconds{artifacts}       histogram of loads: prover, artifacts or verifying_key
+//	zkecdsa_failures_total{operation,kind}                  failures of prove, verify and load by ErrorKind
+//	zkecdsa_proofs_in_flight                                proofs being computed
+//	zkecdsa_peak_memory_bytes                               peak resident memory of the process
+func EnableMetrics(reg prometheus.Registerer) error {
+	m := &metricSet{
+		prove: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "zkecdsa_prove_duration_seconds",
+			Help:    "Time spent computing a proof, witness solving included.",
+			Buckets: prometheus.ExponentialBuckets(0.25, 2, 10),
+		}, []string{"circuit", "curve", "backend"}),
+		verify: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "zkecdsa_verify_duration_seconds",
+			Help:    "Time spent verifying a proof.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 12),
+		}, []string{"backend"}),
+		load: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "zkecdsa_artifact_load_duration_seconds",
+			Help:    "Time spent loading and checking artifacts from disk.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"artifacts"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "zkecdsa_failures_total",
+			Help: "Failed operations by error kind.",
+		}, []string{"operation", "kind"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "zkecdsa_proofs_in_flight",
+			Help: "Proofs being computed.",
+		}),
+	}
+	peak := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "zkecdsa_peak_memory_bytes",
+		Help: "Peak resident memory of the process.",
+	}, func() float64 { return float64(peakMemory()) })
+	for _, c := range []prometheus.Collector{m.prove, m.verify, m.load, m.failures, m.inFlight, peak} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	metrics.Store(m)
+	return nil
+}
+
+// recordLoad records a load of artifacts, the prover, artifacts or
+// verifying_key, that started at start and ended with err.
+func recordLoad(artifacts string, paths Paths, start time.Time, err error) {
+	elapsed := time.Since(start)
+	m := metrics.Load()
+	if err != nil {
+		if m != nil {
+			m.failures.WithLabelValues("load", ErrorKind(err)).Inc()
+		}
+		Logger().Warn("loading artifacts failed", "artifacts", artifacts, "dir", paths.Namespace(), "kind", ErrorKind(err), "error", err)
+		return
+	}
+	if m != nil {
+		m.load.WithLabelValues(artifacts).Observe(elapsed.Seconds())
+	}
+	Logger().Info("artifacts loaded", "artifacts", artifacts, "circuit", paths.Circuit, "curve", paths.Curve, "dir", paths.Namespace(), "duration", elapsed)
+}
+
+// recordProof runs prove, counting it in flight, and records its duration
+// or failure. A panic of prove is recorded as a failure before it goes on.
+func recordProof(circuit CircuitType, curve Curve, backend Backend, prove func() (Proof, witness.Witness, error)) (proof Proof, publicWitness witness.Witness, err error) {
+	start := time.Now()
+	if m := metrics.Load(); m != nil {
+		m.inFlight.Inc()
+	}
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = fmt.Errorf("%w: panic: %v", ErrProvingFailed, r)
+		}
+		elapsed := time.Since(start)
+		m := metrics.Load()
+		if m != nil {
+			m.inFlight.Dec()
+		}
+		attrs := []any{"circuit", circuit, "curve", curve, "backend", backend, "duration", elapsed}
+		if err == nil {
+			if m != nil {
+				m.prove.WithLabelValues(string(circuit), string(curve), string(backend)).Observe(elapsed.Seconds())
+			}
+			Logger().Debug("proof generated", attrs...)
+		} else {
+			if m != nil {
+				m.failures.WithLabelValues("prove", ErrorKind(err)).Inc()
+			}
+			Logger().Log(context.Background(), failureLevel(err), "proving failed", append(attrs, "kind", ErrorKind(err), "error", err)...)
+		}
+		if r != nil {
+			panic(r)
+		}
+	}()
+	return prove()
+}
+
+// recordVerify records a verification with backend that started at start
+// and ended with err.
+func recordVerify(backend Backend, start time.Time, err error) {
+	elapsed := time.Since(start)
+	m := metrics.Load()
+	if m != nil {
+		m.verify.WithLabelValues(string(backend)).Observe(elapsed.Seconds())
+	}
+	if err != nil {
+		if m != nil {
+			m.failures.WithLabelValues("verify", ErrorKind(err)).Inc()
+		}
+		Logger().Log(context.Background(), failureLevel(err), "verification failed", "backend", backend, "duration", elapsed, "kind", ErrorKind(err), "error", err)
+		return
+	}
+	Logger().Debug("proof verified", "backend", backend, "duration", elapsed)
+}
+
+// failureLevel is the log level of a failure: info when the input or proof
+// is at fault, an expected outcome for a service, warn otherwise.
+func failureLevel(err error) slog.Level {
+	switch ErrorKind(err) {
+	case "invalid_input", "signature_invalid", "verification_failed":
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
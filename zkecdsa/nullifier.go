@@ -0,0 +1,97 @@
+package zkecdsa
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// NullifierCircuit verifies an ECDSA signature over the public message hash
+// Msg under a private key, and outputs the public Nullifier of the key in the
+// public Scope, see Nullifier. A relying party rejects a second proof with
+// the same nullifier in a scope without learning the key.
+type NullifierCircuit[T, S emulated.FieldParams] struct {
+	Sig       ecdsa.Signature[S]
+	Msg       emulated.Element[S] `gnark:",public"`
+	Pub       ecdsa.PublicKey[T, S]
+	Scope     frontend.Variable `gnark:",public"`
+	Nullifier frontend.Variable `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *NullifierCircuit[T, S]) Define(api frontend.API) error {
+	signature := EcdsaCircuit[T, S]{Sig: c.Sig, Msg: c.Msg, Pub: c.Pub}
+	if err := signature.Define(api); err != nil {
+		return err
+	}
+	hasher, err := c.hash.newHasher(api)
+	if err != nil {
+		return err
+	}
+	field, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	// The signature verification accepts X+p and Y+p as well as X and Y,
+	// which would give the key several nullifiers: hash the reduced limbs
+	hasher.Write(field.ReduceStrict(&c.Pub.X).Limbs...)
+	hasher.Write(field.ReduceStrict(&c.Pub.Y).Limbs...)
+	hasher.Write(c.Scope)
+	api.AssertIsEqual(hasher.Sum(), c.Nullifier)
+	return nil
+}
+
+// Nullifier computes off-circuit the nullifier checked by NullifierCircuit:
+// the hash of the limbs of the public key coordinates, as for KeyLeaf,
+// followed by scope. Anyone knowing the public key can compute it, so the
+// key must stay private for the nullifier not to identify the signer.
+func Nullifier(hash FieldHash, curve Curve, pubX, pubY, scope *big.Int) (*big.Int, error) {
+	if scope.Sign() < 0 || scope.Cmp(fr.Modulus()) >= 0 {
+		return nil, fmt.Errorf("scope is not a BN254 scalar field element")
+	}
+	switch curve.canonical() {
+	case P256:
+		return nullifier[emulated.P256Fp](hash, pubX, pubY, scope)
+	case Secp256k1:
+		return nullifier[emulated.Secp256k1Fp](hash, pubX, pubY, scope)
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func nullifier[T emulated.FieldParams](hash FieldHash, pubX, pubY, scope *big.Int) (*big.Int, error) {
+	elements := append(limbs[T](pubX), limbs[T](pubY)...)
+	return hash.Sum(append(elements, scope)...)
+}
+
+// NewScope derives a scope from a label naming it, such as an application
+// and action ("example.org/vote/2026"): SHA-256 of the label reduced into
+// the BN254 scalar field.
+func NewScope(label string) *big.Int {
+	digest := sha256.Sum256([]byte(label))
+	var scope fr.Element
+	scope.SetBytes(digest[:])
+	return scope.BigInt(new(big.Int))
+}
+
+// NewNullifierCircuit returns the empty nullifier circuit definition to
+// compile for curve, hashing the nullifier with hash.
+func NewNullifierCircuit(curve Curve, hash FieldHash) (frontend.Circuit, error) {
+	if _, err := ParseFieldHash(string(hash)); err != nil {
+		return nil, err
+	}
+	switch curve.canonical() {
+	case P256:
+		return &NullifierCircuit[emulated.P256Fp, emulated.P256Fr]{hash: hash.canonical()}, nil
+	case Secp256k1:
+		return &NullifierCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{hash: hash.canonical()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
@@ -0,0 +1,205 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// NullifierInput struct for JSON serialization of nullifier circuit witness
+// inputs: the signature input extended with the scope of the nullifier.
+type NullifierInput struct {
+	ProveInputEcdsa
+
+	Scope     string `json:"scope"`               // Hex string of the scope, see NewScope
+	Nullifier string `json:"nullifier,omitempty"` // Hex string of the nullifier, computed from the key if empty
+
+	// Filled in from the artifacts by Prover if unset
+	NullifierHash FieldHash `json:"nullifierHash,omitempty"` // Hash of the nullifier, MiMC if empty
+}
+
+// Circuit returns CircuitNullifier.
+func (in *NullifierInput) Circuit() CircuitType { return CircuitNullifier }
+
+func (in *NullifierInput) curve() Curve { return in.Curve }
+
+func (in *NullifierInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// forCircuit returns in with the nullifier hash of the circuit compiled over
+// hash. A hash already set in in must match.
+func (in *NullifierInput) forCircuit(hash FieldHash) (*NullifierInput, error) {
+	shaped := *in
+	if shaped.NullifierHash == "" {
+		shaped.NullifierHash = hash.canonical()
+	} else if shaped.NullifierHash.canonical() != hash.canonical() {
+		return nil, fmt.Errorf("input nullifier is hashed with %s but the prover was loaded for %s", shaped.NullifierHash, hash.canonical())
+	}
+	return &shaped, nil
+}
+
+// decodedNullifier holds the values of a NullifierInput once the hex is
+// decoded.
+type decodedNullifier struct {
+	*decodedInput
+	scope     *big.Int
+	nullifier *big.Int
+}
+
+// decodePublic decodes the message hash, the scope and the nullifier, which
+// the nullifier circuit exposes. The nullifier must be set.
+func (in *NullifierInput) decodePublic() (*decodedNullifier, error) {
+	msgHashBytes, err := decodeFixed("MsgHash", in.MsgHash, MsgHashLen)
+	if err != nil {
+		return nil, err
+	}
+	scopeBytes, err := decodeHex("Scope", in.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if in.Nullifier == "" {
+		return nil, fmt.Errorf("missing nullifier")
+	}
+	nullifierBytes, err := decodeHex("Nullifier", in.Nullifier)
+	if err != nil {
+		return nil, err
+	}
+	d := &decodedNullifier{
+		decodedInput: &decodedInput{msgHash: msgHashBytes},
+		scope:        new(big.Int).SetBytes(scopeBytes),
+		nullifier:    new(big.Int).SetBytes(nullifierBytes),
+	}
+	// Reject aliases modulo the field, which would verify the same proof
+	// under another store key
+	if d.scope.Cmp(fr.Modulus()) >= 0 || d.nullifier.Cmp(fr.Modulus()) >= 0 {
+		return nil, fmt.Errorf("scope and nullifier must be BN254 scalar field elements")
+	}
+	return d, nil
+}
+
+// decode decodes every field of the input, computing the nullifier and
+// checking it matches Nullifier if set.
+func (in *NullifierInput) decode() (*decodedNullifier, error) {
+	d, err := in.ProveInputEcdsa.decode()
+	if err != nil {
+		return nil, err
+	}
+	scopeBytes, err := decodeHex("Scope", in.Scope)
+	if err != nil {
+		return nil, err
+	}
+	n := &decodedNullifier{decodedInput: d, scope: new(big.Int).SetBytes(scopeBytes)}
+	if n.nullifier, err = Nullifier(in.NullifierHash, in.Curve, d.pubX, d.pubY, n.scope); err != nil {
+		return nil, err
+	}
+	if in.Nullifier != "" {
+		nullifierBytes, err := decodeHex("Nullifier", in.Nullifier)
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).SetBytes(nullifierBytes).Cmp(n.nullifier) != 0 {
+			return nil, fmt.Errorf("key and scope do not match the nullifier")
+		}
+	}
+	return n, nil
+}
+
+// Assignment builds the full NullifierCircuit assignment over the input
+// curve. publicInputs is ignored, the message hash, scope and nullifier are
+// always public.
+func (in *NullifierInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return nullifierAssignment[emulated.P256Fp, emulated.P256Fr](d), nil
+	case Secp256k1:
+		return nullifierAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the NullifierCircuit assignment from the message
+// hash, the scope and the nullifier alone. The key and signature are ignored.
+func (in *NullifierInput) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return &NullifierCircuit[emulated.P256Fp, emulated.P256Fr]{
+			Msg:       emulated.ValueOf[emulated.P256Fr](d.msgHash),
+			Scope:     d.scope,
+			Nullifier: d.nullifier,
+		}, nil
+	case Secp256k1:
+		return &NullifierCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			Msg:       emulated.ValueOf[emulated.Secp256k1Fr](d.msgHash),
+			Scope:     d.scope,
+			Nullifier: d.nullifier,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func nullifierAssignment[T, S emulated.FieldParams](d *decodedNullifier) frontend.Circuit {
+	return &NullifierCircuit[T, S]{
+		Sig: ecdsa.Signature[S]{
+			R: emulated.ValueOf[S](d.r),
+			S: emulated.ValueOf[S](d.s),
+		},
+		Msg: emulated.ValueOf[S](d.msgHash),
+		Pub: ecdsa.PublicKey[T, S]{
+			X: emulated.ValueOf[T](d.pubX),
+			Y: emulated.ValueOf[T](d.pubY),
+		},
+		Scope:     d.scope,
+		Nullifier: d.nullifier,
+	}
+}
+
+// scopeAndNullifier returns the decoded scope and nullifier of the input,
+// as recorded in a NullifierStore.
+func (in *NullifierInput) scopeAndNullifier() (scope, nullifier *big.Int, err error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.scope, d.nullifier, nil
+}
+
+// GenerateNullifierInput signs sha256(msg) with a fresh key on curve and
+// returns the nullifier circuit witness input for scope, the nullifier being
+// hashed with hash.
+func GenerateNullifierInput(curve Curve, msg []byte, scope *big.Int, hash FieldHash) (*NullifierInput, error) {
+	signed, err := GenerateInput(curve, msg)
+	if err != nil {
+		return nil, err
+	}
+	d, err := signed.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	nullifier, err := Nullifier(hash, curve, d.pubX, d.pubY, scope)
+	if err != nil {
+		return nil, err
+	}
+	return &NullifierInput{
+		ProveInputEcdsa: *signed,
+		Scope:           fieldHex(scope),
+		Nullifier:       fieldHex(nullifier),
+		NullifierHash:   hash.canonical(),
+	}, nil
+}
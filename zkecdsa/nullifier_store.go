@@ -0,0 +1,139 @@
+package zkecdsa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNullifierUsed is returned when a nullifier was already used in its scope.
+var ErrNullifierUsed = errors.New("nullifier already used")
+
+// NullifierStore records the nullifiers used in each scope. Implementations
+// are safe for concurrent use.
+type NullifierStore interface {
+	// Used reports whether nullifier was already used in scope.
+	Used(scope, nullifier *big.Int) (bool, error)
+	// Use records nullifier as used in scope, or returns ErrNullifierUsed if
+	// it already was.
+	Use(scope, nullifier *big.Int) error
+}
+
+// nullifierKey is the map key of a nullifier in its scope.
+func nullifierKey(scope, nullifier *big.Int) string {
+	return fieldHex(scope) + " " + fieldHex(nullifier)
+}
+
+// MemoryNullifierStore is a NullifierStore kept in memory, lost on exit.
+type MemoryNullifierStore struct {
+	mu   sync.Mutex
+	used map[string]struct{}
+}
+
+// NewMemoryNullifierStore returns an empty in-memory store.
+func NewMemoryNullifierStore() *MemoryNullifierStore {
+	return &MemoryNullifierStore{used: make(map[string]struct{})}
+}
+
+func (s *MemoryNullifierStore) Used(scope, nullifier *big.Int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, used := s.used[nullifierKey(scope, nullifier)]
+	return used, nil
+}
+
+func (s *MemoryNullifierStore) Use(scope, nullifier *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := nullifierKey(scope, nullifier)
+	if _, used := s.used[key]; used {
+		return ErrNullifierUsed
+	}
+	s.used[key] = struct{}{}
+	return nil
+}
+
+// FileNullifierStore is a NullifierStore persisted to an append-only file,
+// one "<scope hex> <nullifier hex>" line per used nullifier. The file is read
+// once when opened; a single process must own it.
+type FileNullifierStore struct {
+	memory *MemoryNullifierStore
+	mu     sync.Mutex
+	file   *os.File
+}
+
+// OpenFileNullifierStore opens the store at filename, creating it if needed.
+func OpenFileNullifierStore(filename string) (*FileNullifierStore, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening nullifier store %s: %w", filename, err)
+	}
+	s := &FileNullifierStore{memory: NewMemoryNullifierStore(), file: file}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			file.Close()
+			return nil, fmt.Errorf("invalid nullifier store %s: malformed line %d", filename, line)
+		}
+		s.memory.used[strings.Join(fields, " ")] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error reading nullifier store %s: %w", filename, err)
+	}
+	return s, nil
+}
+
+func (s *FileNullifierStore) Used(scope, nullifier *big.Int) (bool, error) {
+	return s.memory.Used(scope, nullifier)
+}
+
+// Use records the nullifier, syncing the file before it returns.
+func (s *FileNullifierStore) Use(scope, nullifier *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if used, _ := s.memory.Used(scope, nullifier); used {
+		return ErrNullifierUsed
+	}
+	if _, err := fmt.Fprintln(s.file, nullifierKey(scope, nullifier)); err != nil {
+		return fmt.Errorf("error writing nullifier store %s: %w", s.file.Name(), err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("error syncing nullifier store %s: %w", s.file.Name(), err)
+	}
+	return s.memory.Use(scope, nullifier)
+}
+
+// Close closes the store file.
+func (s *FileNullifierStore) Close() error {
+	return s.file.Close()
+}
+
+// VerifyNullifier checks that proof was produced for the public values of
+// input, the message hash, scope and nullifier, and records the nullifier in
+// store. It returns ErrNullifierUsed, without verifying the proof, if the
+// nullifier was already used in the scope.
+func VerifyNullifier(proof Proof, vk VerifyingKey, input *NullifierInput, store NullifierStore) error {
+	scope, nullifier, err := input.scopeAndNullifier()
+	if err != nil {
+		return err
+	}
+	if used, err := store.Used(scope, nullifier); err != nil {
+		return err
+	} else if used {
+		return ErrNullifierUsed
+	}
+	if err := VerifyWithPublicInputs(proof, vk, input); err != nil {
+		return err
+	}
+	// Use checks again, a concurrent verification may have won the race
+	return store.Use(scope, nullifier)
+}
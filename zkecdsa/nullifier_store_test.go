@@ -0,0 +1,129 @@
+package zkecdsa
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testNullifierStores returns a fresh store of each kind.
+func testNullifierStores(t *testing.T) map[string]NullifierStore {
+	t.Helper()
+	file, err := OpenFileNullifierStore(filepath.Join(t.TempDir(), "nullifiers.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return map[string]NullifierStore{
+		"memory": NewMemoryNullifierStore(),
+		"file":   file,
+	}
+}
+
+func TestNullifierStoreUse(t *testing.T) {
+	scopeA, scopeB := NewScope("example.org/vote/1"), NewScope("example.org/vote/2")
+	nullifier := big.NewInt(42)
+	steps := []struct {
+		name      string
+		scope     *big.Int
+		nullifier *big.Int
+		want      error
+	}{
+		{"first use", scopeA, nullifier, nil},
+		{"second use in the scope", scopeA, nullifier, ErrNullifierUsed},
+		{"other scope", scopeB, nullifier, nil},
+		{"other nullifier", scopeA, big.NewInt(43), nil},
+	}
+	for name, store := range testNullifierStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, step := range steps {
+				used, err := store.Used(step.scope, step.nullifier)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if used != (step.want != nil) {
+					t.Errorf("%s: Used = %v before Use", step.name, used)
+				}
+				if err := store.Use(step.scope, step.nullifier); !errors.Is(err, step.want) {
+					t.Errorf("%s: Use = %v, want %v", step.name, err, step.want)
+				}
+				if used, _ := store.Used(step.scope, step.nullifier); !used {
+					t.Errorf("%s: Used = false after Use", step.name)
+				}
+			}
+		})
+	}
+}
+
+func TestNullifierStoreConcurrentUse(t *testing.T) {
+	scope, nullifier := NewScope("example.org/claim"), big.NewInt(7)
+	for name, store := range testNullifierStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			errs := make([]error, 16)
+			for i := range errs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = store.Use(scope, nullifier)
+				}()
+			}
+			wg.Wait()
+			succeeded := 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case !errors.Is(err, ErrNullifierUsed):
+					t.Errorf("Use = %v", err)
+				}
+			}
+			if succeeded != 1 {
+				t.Errorf("%d concurrent uses succeeded, want 1", succeeded)
+			}
+		})
+	}
+}
+
+func TestFileNullifierStoreReopen(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "nullifiers.txt")
+	scope, nullifier := NewScope("example.org/vote/1"), big.NewInt(42)
+	store, err := OpenFileNullifierStore(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Use(scope, nullifier); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenFileNullifierStore(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if used, _ := store.Used(scope, nullifier); !used {
+		t.Error("nullifier not used after reopening the store")
+	}
+	if err := store.Use(scope, nullifier); !errors.Is(err, ErrNullifierUsed) {
+		t.Errorf("Use after reopening = %v, want ErrNullifierUsed", err)
+	}
+	if used, _ := store.Used(scope, big.NewInt(43)); used {
+		t.Error("unknown nullifier reported as used")
+	}
+}
+
+func TestOpenFileNullifierStoreMalformed(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "nullifiers.txt")
+	if err := os.WriteFile(filename, []byte("00 01\n02\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileNullifierStore(filename); err == nil {
+		t.Error("OpenFileNullifierStore accepted a line with one field")
+	}
+}
@@ -0,0 +1,69 @@
+package zkecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+func TestNullifierCircuit(t *testing.T) {
+	for _, hash := range FieldHashes {
+		t.Run(string(hash), func(t *testing.T) {
+			t.Run(string(P256), func(t *testing.T) {
+				testNullifierCircuit[emulated.P256Fp, emulated.P256Fr](t, P256, hash)
+			})
+			t.Run(string(Secp256k1), func(t *testing.T) {
+				testNullifierCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](t, Secp256k1, hash)
+			})
+		})
+	}
+}
+
+func testNullifierCircuit[T, S emulated.FieldParams](t *testing.T, curve Curve, hash FieldHash) {
+	scope := NewScope("example.org/vote/1")
+	in, err := GenerateNullifierInput(curve, []byte("nullifier"), scope, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubX, _ := new(big.Int).SetString(in.PubX, 16)
+	pubY, _ := new(big.Int).SetString(in.PubY, 16)
+	// The nullifier a relying party computes for a key it knows
+	nullifier, err := Nullifier(hash, curve, pubX, pubY, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit, err := NewNullifierCircuit(curve, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		scope     *big.Int
+		nullifier *big.Int
+		valid     bool
+	}{
+		{"off-circuit nullifier", scope, nullifier, true},
+		{"other nullifier", scope, new(big.Int).Add(nullifier, big.NewInt(1)), false},
+		{"nullifier of another scope", NewScope("example.org/vote/2"), nullifier, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment, err := in.Assignment(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := assignment.(*NullifierCircuit[T, S])
+			c.Scope, c.Nullifier = tt.scope, tt.nullifier
+			err = test.IsSolved(circuit, c, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
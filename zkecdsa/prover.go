@@ -0,0 +1,531 @@
+package zkecdsa
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+)
+
+var (
+	// ErrSignatureInvalid is returned when a well-formed input does not
+	// satisfy the circuit: a signature does not verify, or a key path,
+	// commitment or threshold does not hold. It matches ErrInvalidInput.
+	ErrSignatureInvalid = errors.New("signature does not verify")
+	// ErrProvingFailed is returned when the prover fails on a satisfiable input.
+	ErrProvingFailed = errors.New("proving failed")
+	// ErrVerificationFailed is returned when a proof does not verify.
+	ErrVerificationFailed = errors.New("verification failed")
+)
+
+// ErrorKind classifies err by the sentinel error it matches, as the error
+// codes of the C bindings do: signature_invalid, invalid_input,
+// artifact_missing, artifact_corrupt, verification_failed, proving_failed,
+// or internal for the other errors. Logs and metrics label failures with it.
+func ErrorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrSignatureInvalid):
+		return "signature_invalid"
+	case errors.Is(err, ErrInvalidInput):
+		return "invalid_input"
+	case errors.Is(err, ErrArtifactMissing):
+		return "artifact_missing"
+	case errors.Is(err, ErrArtifactCorrupt):
+		return "artifact_corrupt"
+	case errors.Is(err, ErrVerificationFailed):
+		return "verification_failed"
+	case errors.Is(err, ErrProvingFailed):
+		return "proving_failed"
+	default:
+		return "internal"
+	}
+}
+
+// HasPublicInputs reports whether ccs was compiled from a circuit with public
+// inputs. EcdsaCircuit only exposes the constant wire.
+func HasPublicInputs(ccs constraint.ConstraintSystem) bool {
+	return ccs.GetNbPublicVariables() > 1
+}
+
+// Config selects the circuit to compile and the proof system to set it up for.
+type Config struct {
+	Circuit      CircuitType // Statement to prove, CircuitECDSA if empty
+	Curve        Curve       // Signature curve, P256 if empty
+	Backend      Backend     // Proof system, Groth16 if empty
+	PublicInputs bool        // ECDSA and message only: compile the variant with public inputs
+	SRS          string      // PLONK only: KZG SRS file, an unsafe SRS is generated if empty
+
+	// Message circuit only
+	MaxMessageLen int  // Message capacity in bytes, DefaultMaxMessageLen if zero
+	CommitMessage bool // Compile MessageCommitmentCircuit, the message staying private
+
+	// Batch circuit only
+	BatchSize int // Signatures per proof, DefaultBatchSize if zero
+
+	// Membership circuit only
+	MerkleDepth int // Depth of the key tree, DefaultMerkleDepth if zero
+
+	// Threshold circuit only
+	Signers         int  // Number of keys n, DefaultThresholdSigners if zero
+	Threshold       int  // Signatures required k, DefaultThreshold if zero
+	PublicThreshold bool // Make k a public input instead of compiling Threshold in
+	CommitKeys      bool // Compile the variant where the keys stay private behind a commitment
+
+	CommitmentHash FieldHash // Message, batch, membership, nullifier and threshold circuits: hash of the commitment, key tree or nullifier, MiMC if empty
+}
+
+// maxMessageLen returns the configured message capacity.
+func (cfg Config) maxMessageLen() int {
+	if cfg.MaxMessageLen == 0 {
+		return DefaultMaxMessageLen
+	}
+	return cfg.MaxMessageLen
+}
+
+// batchSize returns the configured batch size.
+func (cfg Config) batchSize() int {
+	if cfg.BatchSize == 0 {
+		return DefaultBatchSize
+	}
+	return cfg.BatchSize
+}
+
+// merkleDepth returns the configured key tree depth.
+func (cfg Config) merkleDepth() int {
+	if cfg.MerkleDepth == 0 {
+		return DefaultMerkleDepth
+	}
+	return cfg.MerkleDepth
+}
+
+// signers returns the configured number of threshold keys.
+func (cfg Config) signers() int {
+	if cfg.Signers == 0 {
+		return DefaultThresholdSigners
+	}
+	return cfg.Signers
+}
+
+// threshold returns the configured threshold, zero if it is a public input.
+func (cfg Config) threshold() int {
+	switch {
+	case cfg.PublicThreshold:
+		return 0
+	case cfg.Threshold == 0:
+		return DefaultThreshold
+	default:
+		return cfg.Threshold
+	}
+}
+
+// Compile compiles the selected circuit variant over BN254, as an R1CS for
+// Groth16 or a sparse R1CS for PLONK.
+func Compile(cfg Config) (constraint.ConstraintSystem, error) {
+	var (
+		circuit frontend.Circuit
+		err     error
+	)
+	switch cfg.Circuit.canonical() {
+	case CircuitECDSA:
+		circuit, err = NewCircuit(cfg.Curve, cfg.PublicInputs)
+	case CircuitWebAuthn:
+		circuit, err = NewWebAuthnCircuit(cfg.Curve)
+	case CircuitMessage:
+		circuit, err = NewMessageCircuit(cfg.Curve, cfg.maxMessageLen(), cfg.PublicInputs, cfg.CommitMessage, cfg.CommitmentHash)
+	case CircuitBatch:
+		circuit, err = NewBatchCircuit(cfg.Curve, cfg.batchSize(), cfg.CommitmentHash)
+	case CircuitMembership:
+		circuit, err = NewMembershipCircuit(cfg.Curve, cfg.merkleDepth(), cfg.CommitmentHash)
+	case CircuitNullifier:
+		circuit, err = NewNullifierCircuit(cfg.Curve, cfg.CommitmentHash)
+	case CircuitThreshold:
+		circuit, err = NewThresholdCircuit(cfg.Curve, cfg.signers(), cfg.threshold(), cfg.CommitKeys, cfg.CommitmentHash)
+	default:
+		err = fmt.Errorf("unsupported circuit type %q", cfg.Circuit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	builder, err := cfg.Backend.builder()
+	if err != nil {
+		return nil, err
+	}
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling %s circuit: %w", cfg.Circuit.canonical(), err)
+	}
+	return ccs, nil
+}
+
+// Setup compiles the selected circuit variant and runs the setup of the
+// selected backend.
+func Setup(cfg Config) (*Artifacts, error) {
+	ccs, err := Compile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	a := &Artifacts{Circuit: cfg.Circuit.canonical(), Curve: cfg.Curve.canonical(), Backend: cfg.Backend.canonical(), CCS: ccs}
+	switch a.Circuit {
+	case CircuitMessage:
+		a.MaxMessageLen = cfg.maxMessageLen()
+		if cfg.CommitMessage {
+			a.CommitmentHash = cfg.CommitmentHash.canonical()
+		}
+	case CircuitBatch:
+		a.BatchSize = cfg.batchSize()
+		a.CommitmentHash = cfg.CommitmentHash.canonical()
+	case CircuitMembership:
+		a.MerkleDepth = cfg.merkleDepth()
+		a.CommitmentHash = cfg.CommitmentHash.canonical()
+	case CircuitNullifier:
+		a.CommitmentHash = cfg.CommitmentHash.canonical()
+	case CircuitThreshold:
+		a.Signers, a.Threshold = cfg.signers(), cfg.threshold()
+		if cfg.CommitKeys {
+			a.CommitmentHash = cfg.CommitmentHash.canonical()
+		}
+	}
+	switch a.Backend {
+	case Groth16:
+		pk, vk, err := groth16.Setup(ccs)
+		if err != nil {
+			return nil, fmt.Errorf("error during Groth16 setup for %s: %w", a.Circuit, err)
+		}
+		a.PK, a.VK = pk, vk
+	case Plonk:
+		var canonical, lagrange kzg.SRS
+		if cfg.SRS != "" {
+			canonical, lagrange, err = LoadSRS(cfg.SRS, ccs)
+		} else {
+			canonical, lagrange, err = UnsafeSRS(ccs)
+		}
+		if err != nil {
+			return nil, err
+		}
+		pk, vk, err := plonk.Setup(ccs, canonical, lagrange)
+		if err != nil {
+			return nil, fmt.Errorf("error during PLONK setup for %s: %w", a.Circuit, err)
+		}
+		a.PK, a.VK = pk, vk
+	}
+	return a, nil
+}
+
+// Prove builds the witness for input and proves it against ccs and pk, which
+// must have been compiled for the circuit and curve of input. The backend
+// follows from pk. It returns the proof together with the public witness to
+// verify it against.
+//
+// Proofs use the hash-to-field of the exported Solidity verifier, so every
+// proof can also be checked on-chain. An input failing the checks of
+// ValidateInput is reported as ErrInvalidInput before any proving work, one
+// the circuit rejects as ErrSignatureInvalid and any other failure as
+// ErrProvingFailed.
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, input Input) (Proof, witness.Witness, error) {
+	backend, _ := BackendOf(pk)
+	return recordProof(input.Circuit(), input.curve().canonical(), backend, func() (Proof, witness.Witness, error) {
+		return prove(ccs, pk, input)
+	})
+}
+
+func prove(ccs constraint.ConstraintSystem, pk ProvingKey, input Input) (Proof, witness.Witness, error) {
+	backend, err := BackendOf(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	assignment, err := input.Assignment(HasPublicInputs(ccs))
+	if err != nil {
+		return nil, nil, invalidInput(err)
+	}
+	witnessFull, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating full witness: %w", err)
+	}
+	publicWitness, err := witnessFull.Public()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting public witness: %w", err)
+	}
+	var proof Proof
+	target := solidity.WithProverTargetSolidityVerifier(backend.id())
+	switch backend {
+	case Groth16:
+		proof, err = groth16.Prove(ccs, pk.(groth16.ProvingKey), witnessFull, target)
+	case Plonk:
+		proof, err = plonk.Prove(ccs, pk.(plonk.ProvingKey), witnessFull, target)
+	}
+	var unsatisfied *cs.UnsatisfiedConstraintError
+	if errors.As(err, &unsatisfied) {
+		return nil, nil, invalidInput(fmt.Errorf("%w: %w", ErrSignatureInvalid, err))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrProvingFailed, err)
+	}
+	return proof, publicWitness, nil
+}
+
+// Verify checks proof against vk and the given public witness, reporting
+// ErrVerificationFailed if it does not hold. The backend follows from vk.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
+	start := time.Now()
+	err := verify(proof, vk, publicWitness)
+	backend, _ := BackendOf(vk)
+	recordVerify(backend, start, err)
+	return err
+}
+
+func verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness) error {
+	backend, err := BackendOf(vk)
+	if err != nil {
+		return err
+	}
+	if proofBackend, err := BackendOf(proof); err != nil || proofBackend != backend {
+		return fmt.Errorf("%w: not a %s proof", ErrVerificationFailed, backend)
+	}
+	target := solidity.WithVerifierTargetSolidityVerifier(backend.id())
+	switch backend {
+	case Groth16:
+		err = groth16.Verify(proof.(groth16.Proof), vk.(groth16.VerifyingKey), publicWitness, target)
+	case Plonk:
+		err = plonk.Verify(proof.(plonk.Proof), vk.(plonk.VerifyingKey), publicWitness, target)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+	}
+	return nil
+}
+
+// PublicWitness rebuilds the public witness from the public values of input,
+// as a relying party would: the message hash and public key for
+// EcdsaPublicCircuit, the challenge and public key for WebAuthnCircuit, the
+// commitment for BatchCircuit, the message hash and key tree root for
+// MembershipCircuit, the message hash, scope and nullifier for
+// NullifierCircuit, the message hash, keys or their commitment and public
+// threshold for the threshold circuits.
+func PublicWitness(input Input) (witness.Witness, error) {
+	assignment, err := input.PublicAssignment()
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+	publicWitness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return nil, fmt.Errorf("error creating public witness: %w", err)
+	}
+	return publicWitness, nil
+}
+
+// VerifyWithPublicInputs checks that proof was produced for the public values
+// of input. Only meaningful for artifacts of a circuit with public inputs.
+func VerifyWithPublicInputs(proof Proof, vk VerifyingKey, input Input) error {
+	publicWitness, err := PublicWitness(input)
+	if err != nil {
+		return err
+	}
+	return Verify(proof, vk, publicWitness)
+}
+
+// Prover keeps a deserialized constraint system and proving key in memory so
+// they are loaded once rather than on every proof. Both are only read while
+// proving, so a Prover is safe for concurrent use by multiple goroutines.
+type Prover struct {
+	circuit        CircuitType
+	curve          Curve
+	backend        Backend
+	maxMessageLen  int
+	batchSize      int
+	merkleDepth    int
+	signers        int
+	threshold      int
+	commitmentHash FieldHash
+	policy         ValidationPolicy
+	ccs            constraint.ConstraintSystem
+	pk             ProvingKey
+}
+
+// NewProver loads the constraint system and proving key located by paths,
+// for the backend recorded in the manifest and the encoding recorded in the
+// proving key, after checking them against the digests of the manifest.
+func NewProver(paths Paths) (*Prover, error) {
+	start := time.Now()
+	p, err := newProver(paths)
+	recordLoad("prover", paths.Resolve(), start, err)
+	return p, err
+}
+
+func newProver(paths Paths) (*Prover, error) {
+	if _, err := ParseCurve(string(paths.Curve)); err != nil {
+		return nil, err
+	}
+	paths = paths.Resolve()
+	manifest, err := paths.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.checkFiles(paths, true, false); err != nil {
+		return nil, err
+	}
+	ccs, err := manifest.Backend.newCS()
+	if err != nil {
+		return nil, err
+	}
+	if err := readArtifact("constraint system", paths.R1CS, ccs); err != nil {
+		return nil, err
+	}
+	if err := manifest.checkConstraints(ccs); err != nil {
+		return nil, err
+	}
+	pk, _, err := manifest.readProvingKey(paths.ProvingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Prover{
+		circuit:        paths.Circuit,
+		curve:          paths.Curve,
+		backend:        manifest.Backend,
+		maxMessageLen:  manifest.MaxMessageLen,
+		batchSize:      manifest.BatchSize,
+		merkleDepth:    manifest.MerkleDepth,
+		signers:        manifest.Signers,
+		threshold:      manifest.Threshold,
+		commitmentHash: manifest.CommitmentHash,
+		ccs:            ccs,
+		pk:             pk,
+	}, nil
+}
+
+// Prover returns a Prover over the already loaded artifacts.
+func (a *Artifacts) Prover() *Prover {
+	return &Prover{
+		circuit:        a.Circuit.canonical(),
+		curve:          a.Curve.canonical(),
+		backend:        a.Backend.canonical(),
+		maxMessageLen:  a.MaxMessageLen,
+		batchSize:      a.BatchSize,
+		merkleDepth:    a.MerkleDepth,
+		signers:        a.Signers,
+		threshold:      a.Threshold,
+		commitmentHash: a.CommitmentHash,
+		ccs:            a.CCS,
+		pk:             a.PK,
+	}
+}
+
+// WithValidationPolicy returns a prover sharing the artifacts of p that
+// rejects inputs failing the optional checks of policy.
+func (p *Prover) WithValidationPolicy(policy ValidationPolicy) *Prover {
+	withPolicy := *p
+	withPolicy.policy = policy
+	return &withPolicy
+}
+
+// Circuit returns the circuit type the prover was loaded for.
+func (p *Prover) Circuit() CircuitType {
+	return p.circuit
+}
+
+// Curve returns the curve the prover was loaded for.
+func (p *Prover) Curve() Curve {
+	return p.curve
+}
+
+// Backend returns the backend the prover was loaded for.
+func (p *Prover) Backend() Backend {
+	return p.backend
+}
+
+// ConstraintSystem returns the loaded constraint system.
+func (p *Prover) ConstraintSystem() constraint.ConstraintSystem {
+	return p.ccs
+}
+
+// Prove proves input with the cached artifacts, see Prove. An input without
+// a curve is taken to be on the prover curve, a MessageInput, BatchInput,
+// MembershipInput, NullifierInput or ThresholdInput without a shape to have
+// the shape of the loaded circuit. The input is checked against the
+// validation policy of the prover, see WithValidationPolicy.
+func (p *Prover) Prove(input Input) (Proof, witness.Witness, error) {
+	return recordProof(p.circuit, p.curve, p.backend, func() (Proof, witness.Witness, error) {
+		input, err := p.shape(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if p.policy.RequireLowS {
+			if err := checkLowS(input); err != nil {
+				return nil, nil, err
+			}
+		}
+		return prove(p.ccs, p.pk, input)
+	})
+}
+
+// shape checks input is for the circuit and curve of p and fills in the
+// curve and circuit shape it leaves unset, see Prove.
+func (p *Prover) shape(input Input) (Input, error) {
+	if input.Circuit() != p.circuit {
+		return nil, invalidInput(fmt.Errorf("input is for the %s circuit but the prover was loaded for %s", input.Circuit(), p.circuit))
+	}
+	if input.curve() == "" {
+		input = input.withCurve(p.curve)
+	} else if input.curve().canonical() != p.curve {
+		return nil, invalidInput(fmt.Errorf("input is on curve %s but the prover was loaded for %s", input.curve(), p.curve))
+	}
+	switch in := input.(type) {
+	case *MessageInput:
+		shaped, err := in.forCircuit(p.maxMessageLen, p.commitmentHash != "", p.commitmentHash)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		input = shaped
+	case *BatchInput:
+		shaped, err := in.forCircuit(p.batchSize, p.commitmentHash)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		input = shaped
+	case *MembershipInput:
+		shaped, err := in.forCircuit(p.merkleDepth, p.commitmentHash)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		input = shaped
+	case *NullifierInput:
+		shaped, err := in.forCircuit(p.commitmentHash)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		input = shaped
+	case *ThresholdInput:
+		shaped, err := in.forCircuit(p.signers, p.threshold, p.commitmentHash)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		input = shaped
+	}
+	return input, nil
+}
+
+// ShapeInput returns input completed with the curve and circuit shape of the
+// artifacts m describes, as Prover.Prove does, for a verifier rebuilding the
+// public witness of an input that does not set them.
+func (m *Manifest) ShapeInput(input Input) (Input, error) {
+	p := &Prover{
+		circuit:        m.Circuit.canonical(),
+		curve:          m.Curve.canonical(),
+		backend:        m.Backend.canonical(),
+		maxMessageLen:  m.MaxMessageLen,
+		batchSize:      m.BatchSize,
+		merkleDepth:    m.MerkleDepth,
+		signers:        m.Signers,
+		threshold:      m.Threshold,
+		commitmentHash: m.CommitmentHash,
+	}
+	return p.shape(input)
+}
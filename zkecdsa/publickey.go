@@ -0,0 +1,212 @@
+package zkecdsa
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyteasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// PublicKey is an ECDSA public key given by its affine coordinates.
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// Object identifiers of PKIX ECDSA public keys, from RFC 5480 and SEC 2.
+var (
+	oidPublicKeyECDSA      = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// ParsePublicKey parses a public key on curve given as a PEM "PUBLIC KEY"
+// block (PKIX), a JWK, or the hex of a SEC1 point: compressed, uncompressed,
+// or uncompressed without its 04 prefix. The point is checked to be on curve.
+func ParsePublicKey(curve Curve, text string) (PublicKey, error) {
+	text = strings.TrimSpace(text)
+	if block, _ := pem.Decode([]byte(text)); block != nil {
+		return parsePEMPublicKey(curve, block)
+	}
+	if strings.HasPrefix(text, "{") {
+		return parseJWKPublicKey(curve, text)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(text, "0x"))
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%w: public key is neither PEM, JWK nor hex: %v", ErrInvalidHex, err)
+	}
+	if len(b) == 64 {
+		b = append([]byte{4}, b...)
+	}
+	return parseSEC1Point(curve, b)
+}
+
+func parsePEMPublicKey(curve Curve, block *pem.Block) (PublicKey, error) {
+	if block.Type != "PUBLIC KEY" {
+		return PublicKey{}, fmt.Errorf("%w: unsupported PEM block %q, expected PUBLIC KEY", ErrInvalidEncoding, block.Type)
+	}
+	return parsePKIXPublicKey(curve, block.Bytes)
+}
+
+// parsePKIXPublicKey parses a DER SubjectPublicKeyInfo. crypto/x509 does not
+// know secp256k1, so the structure is read directly.
+func parsePKIXPublicKey(curve Curve, der []byte) (PublicKey, error) {
+	var (
+		spki, algorithm        cryptobyte.String
+		algorithmOID, curveOID asn1.ObjectIdentifier
+		point                  asn1.BitString
+		input                  = cryptobyte.String(der)
+	)
+	if !input.ReadASN1(&spki, cryptobyteasn1.SEQUENCE) || !input.Empty() ||
+		!spki.ReadASN1(&algorithm, cryptobyteasn1.SEQUENCE) ||
+		!algorithm.ReadASN1ObjectIdentifier(&algorithmOID) ||
+		!spki.ReadASN1BitString(&point) || !spki.Empty() || point.BitLength%8 != 0 {
+		return PublicKey{}, fmt.Errorf("%w: malformed PKIX public key", ErrInvalidEncoding)
+	}
+	if !algorithmOID.Equal(oidPublicKeyECDSA) {
+		return PublicKey{}, fmt.Errorf("%w: PKIX public key of algorithm %v, not ECDSA", ErrInvalidEncoding, algorithmOID)
+	}
+	if !algorithm.ReadASN1ObjectIdentifier(&curveOID) || !algorithm.Empty() {
+		return PublicKey{}, fmt.Errorf("%w: PKIX public key without a named curve", ErrInvalidEncoding)
+	}
+	var expected asn1.ObjectIdentifier
+	switch curve.canonical() {
+	case P256:
+		expected = oidNamedCurveP256
+	case Secp256k1:
+		expected = oidNamedCurveSecp256k1
+	default:
+		return PublicKey{}, fmt.Errorf("unsupported curve %q", curve)
+	}
+	if !curveOID.Equal(expected) {
+		return PublicKey{}, fmt.Errorf("%w: PKIX public key on curve %v, not %s", ErrInvalidEncoding, curveOID, curve.canonical())
+	}
+	return parseSEC1Point(curve, point.Bytes)
+}
+
+// parseJWKPublicKey parses an EC JSON Web Key (RFC 7518, RFC 8812 for
+// secp256k1), ignoring its private and metadata members.
+func parseJWKPublicKey(curve Curve, text string) (PublicKey, error) {
+	var jwk struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	if err := json.Unmarshal([]byte(text), &jwk); err != nil {
+		return PublicKey{}, fmt.Errorf("%w: malformed JWK: %v", ErrInvalidEncoding, err)
+	}
+	var crv string
+	switch curve.canonical() {
+	case P256:
+		crv = "P-256"
+	case Secp256k1:
+		crv = "secp256k1"
+	default:
+		return PublicKey{}, fmt.Errorf("unsupported curve %q", curve)
+	}
+	if jwk.Kty != "EC" || jwk.Crv != crv {
+		return PublicKey{}, fmt.Errorf("%w: JWK of type %q on curve %q, expected EC on %s", ErrInvalidEncoding, jwk.Kty, jwk.Crv, crv)
+	}
+	point := []byte{4}
+	for _, coordinate := range []struct{ name, value string }{{"x", jwk.X}, {"y", jwk.Y}} {
+		b, err := base64.RawURLEncoding.DecodeString(coordinate.value)
+		if err != nil {
+			return PublicKey{}, fmt.Errorf("%w: JWK %s is not base64url: %v", ErrInvalidEncoding, coordinate.name, err)
+		}
+		if len(b) != 32 {
+			return PublicKey{}, fmt.Errorf("%w: JWK %s is %d bytes, expected 32", ErrInvalidLength, coordinate.name, len(b))
+		}
+		point = append(point, b...)
+	}
+	return parseSEC1Point(curve, point)
+}
+
+// parseSEC1Point parses a compressed or uncompressed SEC1 point on curve.
+func parseSEC1Point(curve Curve, b []byte) (PublicKey, error) {
+	var key PublicKey
+	switch {
+	case len(b) == 65 && b[0] == 4:
+		key = PublicKey{X: new(big.Int).SetBytes(b[1:33]), Y: new(big.Int).SetBytes(b[33:])}
+	case len(b) == 33 && (b[0] == 2 || b[0] == 3):
+		var err error
+		if key, err = decompressPoint(curve, b); err != nil {
+			return PublicKey{}, err
+		}
+	default:
+		return PublicKey{}, fmt.Errorf("%w: public key is %d bytes, expected a 33-byte compressed or 65-byte uncompressed point", ErrInvalidLength, len(b))
+	}
+	if err := key.check(curve); err != nil {
+		return PublicKey{}, err
+	}
+	return key, nil
+}
+
+// decompressPoint recovers Y from a compressed SEC1 point, its prefix 02 or
+// 03 giving the parity of Y.
+func decompressPoint(curve Curve, b []byte) (PublicKey, error) {
+	switch curve.canonical() {
+	case P256:
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), b)
+		if x == nil {
+			return PublicKey{}, fmt.Errorf("%w %s", ErrPointNotOnCurve, P256)
+		}
+		return PublicKey{X: x, Y: y}, nil
+	case Secp256k1:
+		x := new(big.Int).SetBytes(b[1:])
+		if x.Cmp(fp.Modulus()) >= 0 {
+			return PublicKey{}, fmt.Errorf("%w %s: coordinate out of range", ErrPointNotOnCurve, Secp256k1)
+		}
+		// y^2 = x^3 + 7
+		var fx, rhs, y fp.Element
+		fx.SetBigInt(x)
+		rhs.Square(&fx).Mul(&rhs, &fx).Add(&rhs, new(fp.Element).SetUint64(7))
+		if y.Sqrt(&rhs) == nil {
+			return PublicKey{}, fmt.Errorf("%w %s", ErrPointNotOnCurve, Secp256k1)
+		}
+		yInt := y.BigInt(new(big.Int))
+		if yInt.Bit(0) != uint(b[0]&1) {
+			yInt.Sub(fp.Modulus(), yInt)
+		}
+		return PublicKey{X: x, Y: yInt}, nil
+	default:
+		return PublicKey{}, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+// check reports an error if the key is not a point of curve.
+func (k PublicKey) check(curve Curve) error {
+	switch curve.canonical() {
+	case P256:
+		point := make([]byte, 65)
+		point[0] = 4
+		k.X.FillBytes(point[1:33])
+		k.Y.FillBytes(point[33:])
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return fmt.Errorf("%w %s: %v", ErrPointNotOnCurve, P256, err)
+		}
+	case Secp256k1:
+		if k.X.Cmp(fp.Modulus()) >= 0 || k.Y.Cmp(fp.Modulus()) >= 0 {
+			return fmt.Errorf("%w %s: coordinate out of range", ErrPointNotOnCurve, Secp256k1)
+		}
+		var point secp256k1.G1Affine
+		point.X.SetBigInt(k.X)
+		point.Y.SetBigInt(k.Y)
+		if !point.IsOnCurve() {
+			return fmt.Errorf("%w %s", ErrPointNotOnCurve, Secp256k1)
+		}
+	default:
+		return fmt.Errorf("unsupported curve %q", curve)
+	}
+	return nil
+}
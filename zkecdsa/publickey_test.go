@@ -0,0 +1,138 @@
+package zkecdsa
+
+import (
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// pkixPEM returns the PEM "PUBLIC KEY" block of the uncompressed point on
+// the named curve.
+func pkixPEM(t *testing.T, curveOID asn1.ObjectIdentifier, point []byte) string {
+	t.Helper()
+	der, err := asn1.Marshal(struct {
+		Algorithm struct {
+			Algorithm asn1.ObjectIdentifier
+			Curve     asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}{
+		Algorithm: struct {
+			Algorithm asn1.ObjectIdentifier
+			Curve     asn1.ObjectIdentifier
+		}{oidPublicKeyECDSA, curveOID},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	oids := map[Curve]asn1.ObjectIdentifier{P256: oidNamedCurveP256, Secp256k1: oidNamedCurveSecp256k1}
+	jwkCurves := map[Curve]string{P256: "P-256", Secp256k1: "secp256k1"}
+	for _, curve := range Curves {
+		t.Run(string(curve), func(t *testing.T) {
+			input, err := GenerateInput(curve, []byte("public key"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			x, _ := new(big.Int).SetString(input.PubX, 16)
+			y, _ := new(big.Int).SetString(input.PubY, 16)
+			xBytes, yBytes := x.FillBytes(make([]byte, 32)), y.FillBytes(make([]byte, 32))
+			uncompressed := append(append([]byte{4}, xBytes...), yBytes...)
+			compressed := append([]byte{byte(2 + y.Bit(0))}, xBytes...)
+			otherCurve := Secp256k1
+			if curve == Secp256k1 {
+				otherCurve = P256
+			}
+			jwk := func(crv, x, y string) string {
+				return fmt.Sprintf(`{"kty":"EC","crv":%q,"x":%q,"y":%q,"kid":"k1"}`, crv, x, y)
+			}
+			b64 := base64.RawURLEncoding.EncodeToString
+
+			tests := []struct {
+				name string
+				text string
+				want error // nil for a valid key
+			}{
+				{"uncompressed", hex.EncodeToString(uncompressed), nil},
+				{"uncompressed with 0x", "0x" + hex.EncodeToString(uncompressed), nil},
+				{"coordinates without prefix", hex.EncodeToString(uncompressed[1:]), nil},
+				{"compressed", hex.EncodeToString(compressed), nil},
+				{"PEM", pkixPEM(t, oids[curve], uncompressed), nil},
+				{"PEM of a compressed point", pkixPEM(t, oids[curve], compressed), nil},
+				{"JWK", jwk(jwkCurves[curve], b64(xBytes), b64(yBytes)), nil},
+				{"not hex", "04zz", ErrInvalidHex},
+				{"truncated point", hex.EncodeToString(uncompressed[:40]), ErrInvalidLength},
+				{"unknown prefix", "05" + hex.EncodeToString(uncompressed[1:]), ErrInvalidLength},
+				{"off the curve", hex.EncodeToString(append(append([]byte{4}, xBytes...), xBytes...)), ErrPointNotOnCurve},
+				{"PEM on another curve", pkixPEM(t, oids[otherCurve], uncompressed), ErrInvalidEncoding},
+				{"PEM of a certificate", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0}})), ErrInvalidEncoding},
+				{"JWK on another curve", jwk(jwkCurves[otherCurve], b64(xBytes), b64(yBytes)), ErrInvalidEncoding},
+				{"JWK not base64url", jwk(jwkCurves[curve], "+/+/", b64(yBytes)), ErrInvalidEncoding},
+				{"JWK short coordinate", jwk(jwkCurves[curve], b64(xBytes[1:]), b64(yBytes)), ErrInvalidLength},
+				{"malformed JWK", `{"kty":`, ErrInvalidEncoding},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					key, err := ParsePublicKey(curve, tt.text)
+					if tt.want != nil {
+						if !errors.Is(err, tt.want) {
+							t.Fatalf("ParsePublicKey = %v, want %v", err, tt.want)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					if key.X.Cmp(x) != 0 || key.Y.Cmp(y) != 0 {
+						t.Errorf("ParsePublicKey = (%x, %x), want (%x, %x)", key.X, key.Y, x, y)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestParseDERSignature(t *testing.T) {
+	r, s := big.NewInt(0x1234), new(big.Int).Lsh(big.NewInt(1), 255)
+	der, err := marshalDERSignature(r, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		der   []byte
+		valid bool
+	}{
+		{"valid", der, true},
+		{"trailing data", append(append([]byte(nil), der...), 0), false},
+		{"truncated", der[:len(der)-1], false},
+		{"non-minimal integer", []byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01}, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotR, gotS, err := parseDERSignature(tt.der)
+			if !tt.valid {
+				if err == nil {
+					t.Fatal("parseDERSignature succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotR.Cmp(r) != 0 || gotS.Cmp(s) != 0 {
+				t.Errorf("parseDERSignature = (%x, %x), want (%x, %x)", gotR, gotS, r, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package zkecdsa
+
+import "runtime"
+
+// peakMemory returns the memory obtained from the system by the Go runtime,
+// which it seldom returns, where the peak resident memory is not available.
+func peakMemory() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.Sys)
+}
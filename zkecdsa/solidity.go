@@ -0,0 +1,195 @@
+package zkecdsa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	plonkbn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"golang.org/x/crypto/sha3"
+)
+
+// Default file names of the on-chain outputs, next to the other artifacts.
+const (
+	SolidityVerifierFile = "Verifier.sol"
+	FixtureFile          = "fixture.json"
+)
+
+// ExportSolidity writes the Solidity verifier contract for vk. The Groth16
+// contract exposes verifyProof, the PLONK one Verify; Calldata encodes a call
+// to either. Only meaningful for EcdsaPublicCircuit artifacts: the private
+// variant has no public input to check on-chain.
+func ExportSolidity(w io.Writer, vk VerifyingKey) error {
+	if err := vk.ExportSolidity(w); err != nil {
+		return fmt.Errorf("error exporting Solidity verifier: %w", err)
+	}
+	return nil
+}
+
+// WriteSolidityVerifier writes the Solidity verifier contract for vk to filename.
+func WriteSolidityVerifier(filename string, vk VerifyingKey) error {
+	var buf bytes.Buffer
+	if err := ExportSolidity(&buf, vk); err != nil {
+		return err
+	}
+	return WriteToFile(filename, &buf)
+}
+
+// Fixture holds a proof and its public inputs as the exported verifier takes
+// them, as 0x-prefixed hex, for Foundry tests (vm.readFile + vm.parseJson).
+type Fixture struct {
+	Backend Backend `json:"backend"`
+	// Groth16: the 8 words of the proof, the commitment words and the 2 words
+	// of the commitment proof of knowledge. PLONK: the single proof bytes.
+	Proof         []string `json:"proof"`
+	Commitments   []string `json:"commitments,omitempty"`
+	CommitmentPok []string `json:"commitmentPok,omitempty"`
+	Input         []string `json:"input"`
+	Calldata      string   `json:"calldata"` // Complete call, selector included
+}
+
+// NewFixture builds the Foundry fixture of proof and its public witness.
+func NewFixture(proof Proof, publicWitness witness.Witness) (*Fixture, error) {
+	input, err := publicInputWords(publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := Calldata(proof, publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	f := &Fixture{Input: hexWords(input), Calldata: "0x" + hex.EncodeToString(calldata)}
+	switch p := proof.(type) {
+	case *groth16bn254.Proof:
+		words, commitments, pok := groth16ProofWords(p)
+		f.Backend = Groth16
+		f.Proof = hexWords(words)
+		f.Commitments = hexWords(commitments)
+		f.CommitmentPok = hexWords(pok)
+	case *plonkbn254.Proof:
+		f.Backend = Plonk
+		f.Proof = []string{"0x" + hex.EncodeToString(p.MarshalSolidity())}
+	default:
+		return nil, fmt.Errorf("unsupported proof type %T", proof)
+	}
+	return f, nil
+}
+
+// WriteFixture writes the fixture as indented JSON.
+func WriteFixture(filename string, f *Fixture) error {
+	fixtureJSON, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling fixture JSON: %w", err)
+	}
+	return WriteToFile(filename, bytes.NewReader(fixtureJSON))
+}
+
+// Calldata ABI-encodes the call that checks proof against its public witness
+// in the contract written by ExportSolidity: verifyProof(uint256[8], ...) for
+// Groth16, Verify(bytes, uint256[]) for PLONK.
+func Calldata(proof Proof, publicWitness witness.Witness) ([]byte, error) {
+	input, err := publicInputWords(publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	switch p := proof.(type) {
+	case *groth16bn254.Proof:
+		words, commitments, pok := groth16ProofWords(p)
+		signature := "verifyProof(uint256[8],"
+		if len(commitments) > 0 {
+			signature += fmt.Sprintf("uint256[%d],uint256[2],", len(commitments))
+		}
+		signature += fmt.Sprintf("uint256[%d])", len(input))
+
+		// Static arrays are encoded in place, one word per element
+		calldata := selector(signature)
+		for _, group := range [][][32]byte{words, commitments, pok, input} {
+			for _, w := range group {
+				calldata = append(calldata, w[:]...)
+			}
+		}
+		return calldata, nil
+	case *plonkbn254.Proof:
+		proofBytes := p.MarshalSolidity()
+		paddedLen := (len(proofBytes) + 31) / 32 * 32
+
+		// Head: offsets of the two dynamic arguments. Tail: each argument
+		// prefixed by its length, the bytes right-padded to a word
+		calldata := selector("Verify(bytes,uint256[])")
+		calldata = append(calldata, word(2*32)...)
+		calldata = append(calldata, word(uint64(2*32+32+paddedLen))...)
+		calldata = append(calldata, word(uint64(len(proofBytes)))...)
+		calldata = append(calldata, proofBytes...)
+		calldata = append(calldata, make([]byte, paddedLen-len(proofBytes))...)
+		calldata = append(calldata, word(uint64(len(input)))...)
+		for _, w := range input {
+			calldata = append(calldata, w[:]...)
+		}
+		return calldata, nil
+	default:
+		return nil, fmt.Errorf("unsupported proof type %T", proof)
+	}
+}
+
+// groth16ProofWords splits a Groth16 proof into the uint256 arguments of
+// verifyProof. The raw encoding is Ar | Bs | Krs, the commitments prefixed
+// by their count on 4 bytes, then the proof of knowledge, G2 coordinates
+// already in the A1, A0 order the precompile expects.
+func groth16ProofWords(p *groth16bn254.Proof) (proof, commitments, pok [][32]byte) {
+	raw := p.MarshalSolidity()
+	proof = splitWords(raw[:8*32])
+	if len(p.Commitments) > 0 {
+		rest := raw[8*32+4:]
+		commitments = splitWords(rest[:len(p.Commitments)*64])
+		pok = splitWords(rest[len(p.Commitments)*64:])
+	}
+	return proof, commitments, pok
+}
+
+// publicInputWords returns the public witness as big-endian words.
+func publicInputWords(publicWitness witness.Witness) ([][32]byte, error) {
+	vector, ok := publicWitness.Vector().(fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public witness type %T", publicWitness.Vector())
+	}
+	words := make([][32]byte, len(vector))
+	for i := range vector {
+		words[i] = vector[i].Bytes()
+	}
+	return words, nil
+}
+
+func selector(signature string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+func word(v uint64) []byte {
+	var w [32]byte
+	binary.BigEndian.PutUint64(w[24:], v)
+	return w[:]
+}
+
+func splitWords(b []byte) [][32]byte {
+	words := make([][32]byte, len(b)/32)
+	for i := range words {
+		copy(words[i][:], b[i*32:])
+	}
+	return words
+}
+
+func hexWords(words [][32]byte) []string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		out[i] = "0x" + new(big.Int).SetBytes(w[:]).Text(16)
+	}
+	return out
+}
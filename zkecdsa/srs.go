@@ -0,0 +1,44 @@
+package zkecdsa
+
+import (
+	"fmt"
+
+	kzgbn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+// UnsafeSRS generates a KZG SRS sized for ccs from a locally sampled secret.
+// Whoever ran it could forge proofs, so it is only fit for testing.
+func UnsafeSRS(ccs constraint.ConstraintSystem) (canonical, lagrange kzg.SRS, err error) {
+	canonical, lagrange, err = unsafekzg.NewSRS(ccs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating unsafe KZG SRS: %w", err)
+	}
+	return canonical, lagrange, nil
+}
+
+// LoadSRS reads a BN254 KZG SRS in canonical form, as written by the
+// gnark-crypto kzg.SRS WriteTo (e.g. converted from a ceremony transcript),
+// and derives the Lagrange form sized for ccs.
+func LoadSRS(filename string, ccs constraint.ConstraintSystem) (canonical, lagrange kzg.SRS, err error) {
+	var srs kzgbn254.SRS
+	if err := readArtifact("KZG SRS", filename, &srs); err != nil {
+		return nil, nil, err
+	}
+	sizeCanonical, sizeLagrange := plonk.SRSSize(ccs)
+	if len(srs.Pk.G1) < sizeCanonical {
+		return nil, nil, fmt.Errorf("KZG SRS %s is too small: got %d points, need %d", filename, len(srs.Pk.G1), sizeCanonical)
+	}
+	srs.Pk.G1 = srs.Pk.G1[:sizeCanonical]
+
+	lagrangeG1, err := kzgbn254.ToLagrangeG1(srs.Pk.G1[:sizeLagrange])
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting KZG SRS to Lagrange form: %w", err)
+	}
+	srsLagrange := &kzgbn254.SRS{Vk: srs.Vk}
+	srsLagrange.Pk.G1 = lagrangeG1
+	return &srs, srsLagrange, nil
+}
@@ -0,0 +1,231 @@
+package zkecdsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// DefaultThresholdSigners and DefaultThreshold are the number of keys n and
+// the number of signatures k of the threshold circuit when none is
+// configured.
+const (
+	DefaultThresholdSigners = 3
+	DefaultThreshold        = 2
+)
+
+// ThresholdCircuit verifies that at least threshold of the distinct public
+// keys Pubs signed the public message hash Msg. Sigs[i] is the signature by
+// Pubs[i] and is only checked when the private Enabled[i] bit is set, so the
+// proof does not reveal which keys signed. The threshold is compiled in.
+type ThresholdCircuit[T, S emulated.FieldParams] struct {
+	Sigs    []ecdsa.Signature[S]
+	Enabled []frontend.Variable
+	Msg     emulated.Element[S]     `gnark:",public"`
+	Pubs    []ecdsa.PublicKey[T, S] `gnark:",public"`
+
+	threshold int
+}
+
+func (c *ThresholdCircuit[T, S]) Define(api frontend.API) error {
+	return verifyThreshold(api, c.Sigs, c.Enabled, &c.Msg, c.Pubs, c.threshold)
+}
+
+// ThresholdPublicKCircuit is the variant of ThresholdCircuit where the
+// threshold K is a public input, so one setup serves every threshold.
+type ThresholdPublicKCircuit[T, S emulated.FieldParams] struct {
+	Sigs    []ecdsa.Signature[S]
+	Enabled []frontend.Variable
+	Msg     emulated.Element[S]     `gnark:",public"`
+	Pubs    []ecdsa.PublicKey[T, S] `gnark:",public"`
+	K       frontend.Variable       `gnark:",public"`
+}
+
+func (c *ThresholdPublicKCircuit[T, S]) Define(api frontend.API) error {
+	return verifyThreshold(api, c.Sigs, c.Enabled, &c.Msg, c.Pubs, c.K)
+}
+
+// ThresholdCommitmentCircuit is the variant of ThresholdCircuit where the
+// keys stay private but are bound to the public Commitment, see
+// ThresholdCommitment.
+type ThresholdCommitmentCircuit[T, S emulated.FieldParams] struct {
+	Sigs       []ecdsa.Signature[S]
+	Enabled    []frontend.Variable
+	Msg        emulated.Element[S] `gnark:",public"`
+	Pubs       []ecdsa.PublicKey[T, S]
+	Commitment frontend.Variable `gnark:",public"`
+
+	threshold int
+	hash      FieldHash
+}
+
+func (c *ThresholdCommitmentCircuit[T, S]) Define(api frontend.API) error {
+	if err := verifyThreshold(api, c.Sigs, c.Enabled, &c.Msg, c.Pubs, c.threshold); err != nil {
+		return err
+	}
+	return assertKeysCommitment(api, c.hash, c.Pubs, c.Commitment)
+}
+
+// ThresholdCommitmentPublicKCircuit is the variant of
+// ThresholdCommitmentCircuit where the threshold K is a public input.
+type ThresholdCommitmentPublicKCircuit[T, S emulated.FieldParams] struct {
+	Sigs       []ecdsa.Signature[S]
+	Enabled    []frontend.Variable
+	Msg        emulated.Element[S] `gnark:",public"`
+	Pubs       []ecdsa.PublicKey[T, S]
+	Commitment frontend.Variable `gnark:",public"`
+	K          frontend.Variable `gnark:",public"`
+
+	hash FieldHash
+}
+
+func (c *ThresholdCommitmentPublicKCircuit[T, S]) Define(api frontend.API) error {
+	if err := verifyThreshold(api, c.Sigs, c.Enabled, &c.Msg, c.Pubs, c.K); err != nil {
+		return err
+	}
+	return assertKeysCommitment(api, c.hash, c.Pubs, c.Commitment)
+}
+
+// verifyThreshold checks that the keys are pairwise distinct and that at
+// least k of them signed msg. A disabled signature is replaced by the valid
+// dummy signature of thresholdDummy, so PublicKey.Verify holds whatever the
+// prover put in its place.
+func verifyThreshold[T, S emulated.FieldParams](api frontend.API, sigs []ecdsa.Signature[S], enabled []frontend.Variable, msg *emulated.Element[S], pubs []ecdsa.PublicKey[T, S], k frontend.Variable) error {
+	if len(sigs) != len(pubs) || len(enabled) != len(pubs) {
+		return fmt.Errorf("threshold of %d keys with %d signatures and %d enable bits", len(pubs), len(sigs), len(enabled))
+	}
+	base, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	scalars, err := emulated.NewField[S](api)
+	if err != nil {
+		return err
+	}
+	for i := range pubs {
+		for j := i + 1; j < len(pubs); j++ {
+			sameX := base.IsZero(base.Sub(&pubs[i].X, &pubs[j].X))
+			sameY := base.IsZero(base.Sub(&pubs[i].Y, &pubs[j].Y))
+			api.AssertIsEqual(api.And(sameX, sameY), 0)
+		}
+	}
+
+	curveParams := sw_emulated.GetCurveParams[T]()
+	dummy := thresholdDummy[T, S]()
+	dummyX, dummyY := base.NewElement(dummy.pubX), base.NewElement(dummy.pubY)
+	dummyMsg := scalars.NewElement(dummy.msgHash)
+	dummyR, dummyS := scalars.NewElement(dummy.r), scalars.NewElement(dummy.s)
+	var count frontend.Variable = 0
+	for i := range pubs {
+		api.AssertIsBoolean(enabled[i])
+		pub := ecdsa.PublicKey[T, S]{
+			X: *base.Select(enabled[i], &pubs[i].X, dummyX),
+			Y: *base.Select(enabled[i], &pubs[i].Y, dummyY),
+		}
+		sig := ecdsa.Signature[S]{
+			R: *scalars.Select(enabled[i], &sigs[i].R, dummyR),
+			S: *scalars.Select(enabled[i], &sigs[i].S, dummyS),
+		}
+		pub.Verify(api, curveParams, scalars.Select(enabled[i], msg, dummyMsg), &sig)
+		count = api.Add(count, enabled[i])
+	}
+	api.AssertIsLessOrEqual(k, count)
+	return nil
+}
+
+// assertKeysCommitment asserts that commitment is the ThresholdCommitment of
+// pubs.
+func assertKeysCommitment[T, S emulated.FieldParams](api frontend.API, hash FieldHash, pubs []ecdsa.PublicKey[T, S], commitment frontend.Variable) error {
+	hasher, err := hash.newHasher(api)
+	if err != nil {
+		return err
+	}
+	// Hashing the limbs pins them to the canonical ones of ThresholdCommitment
+	for i := range pubs {
+		hasher.Write(pubs[i].X.Limbs...)
+		hasher.Write(pubs[i].Y.Limbs...)
+	}
+	api.AssertIsEqual(hasher.Sum(), commitment)
+	return nil
+}
+
+// thresholdDummy returns the signature of message hash 1 by private key 1
+// with nonce 1, which verifies under the generator: r = Gx mod n and
+// s = 1 + r mod n.
+func thresholdDummy[T, S emulated.FieldParams]() *decodedInput {
+	var order S
+	params := sw_emulated.GetCurveParams[T]()
+	r := new(big.Int).Mod(params.Gx, order.Modulus())
+	s := new(big.Int).Add(r, big.NewInt(1))
+	s.Mod(s, order.Modulus())
+	return &decodedInput{msgHash: []byte{1}, r: r, s: s, pubX: params.Gx, pubY: params.Gy}
+}
+
+// ThresholdCommitment computes off-circuit the commitment checked by
+// ThresholdCommitmentCircuit: the hash of the X and Y coordinates of every
+// key in order, each split into the limbs of its emulated field.
+func ThresholdCommitment(hash FieldHash, curve Curve, keys []PublicKey) (*big.Int, error) {
+	switch curve.canonical() {
+	case P256:
+		return thresholdCommitment[emulated.P256Fp](hash, keys)
+	case Secp256k1:
+		return thresholdCommitment[emulated.Secp256k1Fp](hash, keys)
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func thresholdCommitment[T emulated.FieldParams](hash FieldHash, keys []PublicKey) (*big.Int, error) {
+	var elements []*big.Int
+	for _, key := range keys {
+		elements = append(elements, limbs[T](key.X)...)
+		elements = append(elements, limbs[T](key.Y)...)
+	}
+	return hash.Sum(elements...)
+}
+
+// NewThresholdCircuit returns the empty threshold circuit definition to
+// compile for curve over signers keys. A zero threshold selects the variants
+// where the threshold is a public input. commit selects the variants where
+// the keys are committed to over hash rather than public.
+func NewThresholdCircuit(curve Curve, signers, threshold int, commit bool, hash FieldHash) (frontend.Circuit, error) {
+	if signers <= 0 {
+		return nil, fmt.Errorf("invalid number of threshold signers %d", signers)
+	}
+	if threshold < 0 || threshold > signers {
+		return nil, fmt.Errorf("invalid threshold %d of %d signers", threshold, signers)
+	}
+	if commit {
+		if _, err := ParseFieldHash(string(hash)); err != nil {
+			return nil, err
+		}
+	}
+	switch curve.canonical() {
+	case P256:
+		return newThresholdCircuit[emulated.P256Fp, emulated.P256Fr](signers, threshold, commit, hash.canonical()), nil
+	case Secp256k1:
+		return newThresholdCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr](signers, threshold, commit, hash.canonical()), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func newThresholdCircuit[T, S emulated.FieldParams](signers, threshold int, commit bool, hash FieldHash) frontend.Circuit {
+	sigs := make([]ecdsa.Signature[S], signers)
+	enabled := make([]frontend.Variable, signers)
+	pubs := make([]ecdsa.PublicKey[T, S], signers)
+	switch {
+	case commit && threshold == 0:
+		return &ThresholdCommitmentPublicKCircuit[T, S]{Sigs: sigs, Enabled: enabled, Pubs: pubs, hash: hash}
+	case commit:
+		return &ThresholdCommitmentCircuit[T, S]{Sigs: sigs, Enabled: enabled, Pubs: pubs, threshold: threshold, hash: hash}
+	case threshold == 0:
+		return &ThresholdPublicKCircuit[T, S]{Sigs: sigs, Enabled: enabled, Pubs: pubs}
+	default:
+		return &ThresholdCircuit[T, S]{Sigs: sigs, Enabled: enabled, Pubs: pubs, threshold: threshold}
+	}
+}
@@ -0,0 +1,303 @@
+package zkecdsa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// ThresholdSigner is one key of a ThresholdInput, with its signature if it
+// signed.
+type ThresholdSigner struct {
+	PubX string `json:"pubX"`        // Hex string of public key X
+	PubY string `json:"pubY"`        // Hex string of public key Y
+	R    string `json:"r,omitempty"` // Hex string of signature R, empty if the key did not sign
+	S    string `json:"s,omitempty"` // Hex string of signature S, empty if the key did not sign
+}
+
+// ThresholdInput struct for JSON serialization of threshold circuit witness
+// inputs: the message hash, and the keys of the set in order with the
+// signatures of those that signed.
+type ThresholdInput struct {
+	MsgHash string            `json:"msgHash"`         // Hex string of the message hash
+	Signers []ThresholdSigner `json:"signers"`         // Every key of the set, in order
+	Curve   Curve             `json:"curve,omitempty"` // Signature curve, P256 if empty
+
+	// Shape of the circuit, filled in from the artifacts by Prover if unset
+	Threshold       int       `json:"threshold,omitempty"`       // Signatures required, the public K with PublicThreshold
+	PublicThreshold bool      `json:"publicThreshold,omitempty"` // The threshold is a public input rather than compiled in
+	CommitmentHash  FieldHash `json:"commitmentHash,omitempty"`  // Commitment variant only: hash of the key set commitment
+
+	// Commitment variant only
+	Commitment string `json:"commitment,omitempty"` // Hex string of the key set commitment, computed from the keys if empty
+}
+
+// Circuit returns CircuitThreshold.
+func (in *ThresholdInput) Circuit() CircuitType { return CircuitThreshold }
+
+func (in *ThresholdInput) curve() Curve { return in.Curve }
+
+func (in *ThresholdInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// forCircuit returns in with the shape of the threshold circuit compiled over
+// signers keys with the given threshold, zero for a public one, and a key
+// commitment over hash if set. A shape already set in in must match.
+func (in *ThresholdInput) forCircuit(signers, threshold int, hash FieldHash) (*ThresholdInput, error) {
+	if len(in.Signers) != signers {
+		return nil, fmt.Errorf("threshold input of %d keys but the prover was loaded for %d", len(in.Signers), signers)
+	}
+	shaped := *in
+	switch {
+	case threshold == 0 && shaped.Threshold == 0:
+		return nil, fmt.Errorf("missing threshold, the prover was loaded for a public threshold")
+	case threshold == 0:
+		shaped.PublicThreshold = true
+	case shaped.PublicThreshold:
+		return nil, fmt.Errorf("input has a public threshold but the prover was loaded for a threshold of %d", threshold)
+	case shaped.Threshold == 0:
+		shaped.Threshold = threshold
+	case shaped.Threshold != threshold:
+		return nil, fmt.Errorf("input is for a threshold of %d but the prover was loaded for %d", shaped.Threshold, threshold)
+	}
+	switch {
+	case hash == "" && shaped.CommitmentHash != "":
+		return nil, fmt.Errorf("input commits to the keys but the prover was loaded without commitment")
+	case hash != "" && shaped.CommitmentHash == "":
+		shaped.CommitmentHash = hash.canonical()
+	case hash != "" && shaped.CommitmentHash.canonical() != hash.canonical():
+		return nil, fmt.Errorf("input commits with %s but the prover was loaded for %s", shaped.CommitmentHash, hash.canonical())
+	}
+	return &shaped, nil
+}
+
+// decodedThreshold holds the values of a ThresholdInput once the hex is
+// decoded. Signatures of keys that did not sign are nil.
+type decodedThreshold struct {
+	msgHash    []byte
+	keys       []PublicKey
+	r, s       []*big.Int
+	commitment *big.Int
+}
+
+// decodePublic decodes the message hash, and either the keys or the
+// commitment depending on the variant.
+func (in *ThresholdInput) decodePublic() (*decodedThreshold, error) {
+	msgHash, err := decodeFixed("MsgHash", in.MsgHash, MsgHashLen)
+	if err != nil {
+		return nil, err
+	}
+	d := &decodedThreshold{msgHash: msgHash}
+	if in.CommitmentHash != "" && in.Commitment != "" {
+		commitmentBytes, err := decodeHex("Commitment", in.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		d.commitment = new(big.Int).SetBytes(commitmentBytes)
+		return d, nil
+	}
+	if err := in.decodeKeys(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// decodeKeys decodes the keys, and computes the commitment of the commitment
+// variant into d.
+func (in *ThresholdInput) decodeKeys(d *decodedThreshold) error {
+	d.keys = make([]PublicKey, len(in.Signers))
+	for i, signer := range in.Signers {
+		pubX, pubY, err := decodePoint(in.Curve, fmt.Sprintf("Signers[%d].", i), signer.PubX, signer.PubY)
+		if err != nil {
+			return err
+		}
+		d.keys[i] = PublicKey{X: pubX, Y: pubY}
+	}
+	if in.CommitmentHash == "" {
+		return nil
+	}
+	commitment, err := ThresholdCommitment(in.CommitmentHash, in.Curve, d.keys)
+	if err != nil {
+		return err
+	}
+	if d.commitment != nil && d.commitment.Cmp(commitment) != 0 {
+		return fmt.Errorf("keys do not match the commitment")
+	}
+	d.commitment = commitment
+	return nil
+}
+
+// decode decodes every field of the input, checking there are enough
+// signatures and no repeated key.
+func (in *ThresholdInput) decode() (*decodedThreshold, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	if d.keys == nil {
+		if err := in.decodeKeys(d); err != nil {
+			return nil, err
+		}
+	}
+	d.r = make([]*big.Int, len(in.Signers))
+	d.s = make([]*big.Int, len(in.Signers))
+	count := 0
+	for i, signer := range in.Signers {
+		for j := range i {
+			if d.keys[i].X.Cmp(d.keys[j].X) == 0 && d.keys[i].Y.Cmp(d.keys[j].Y) == 0 {
+				return nil, fmt.Errorf("keys %d and %d are the same", j, i)
+			}
+		}
+		if signer.R == "" && signer.S == "" {
+			continue
+		}
+		if d.r[i], err = decodeScalar(in.Curve, fmt.Sprintf("Signers[%d].R", i), signer.R); err != nil {
+			return nil, err
+		}
+		if d.s[i], err = decodeScalar(in.Curve, fmt.Sprintf("Signers[%d].S", i), signer.S); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	if count < in.Threshold {
+		return nil, fmt.Errorf("%d signatures for a threshold of %d", count, in.Threshold)
+	}
+	return d, nil
+}
+
+func (in *ThresholdInput) signatureS() ([]*big.Int, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	return d.s, nil
+}
+
+// Assignment builds the full witness assignment for the selected threshold
+// circuit variant over the input curve. publicInputs is ignored, the variant
+// follows from PublicThreshold and CommitmentHash.
+func (in *ThresholdInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return thresholdAssignment[emulated.P256Fp, emulated.P256Fr](in, d), nil
+	case Secp256k1:
+		return thresholdAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](in, d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the assignment of the threshold circuit variant
+// from the message hash, the keys or the commitment when CommitmentHash is
+// set, and the threshold when PublicThreshold is set. The signatures, and
+// given a commitment the keys, are ignored beyond their number.
+func (in *ThresholdInput) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	d.r = make([]*big.Int, len(in.Signers))
+	d.s = make([]*big.Int, len(in.Signers))
+	if d.keys == nil {
+		d.keys = make([]PublicKey, len(in.Signers))
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return thresholdAssignment[emulated.P256Fp, emulated.P256Fr](in, d), nil
+	case Secp256k1:
+		return thresholdAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](in, d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// thresholdAssignment builds the assignment of the variant of in from d,
+// leaving unset the keys and signatures that d lacks. A key without a
+// signature is disabled, its signature assigned zero.
+func thresholdAssignment[T, S emulated.FieldParams](in *ThresholdInput, d *decodedThreshold) frontend.Circuit {
+	n := len(d.keys)
+	sigs := make([]ecdsa.Signature[S], n)
+	enabled := make([]frontend.Variable, n)
+	pubs := make([]ecdsa.PublicKey[T, S], n)
+	for i := range n {
+		enabled[i] = 0
+		sigs[i] = ecdsa.Signature[S]{R: emulated.ValueOf[S](0), S: emulated.ValueOf[S](0)}
+		if d.r[i] != nil {
+			enabled[i] = 1
+			sigs[i] = ecdsa.Signature[S]{R: emulated.ValueOf[S](d.r[i]), S: emulated.ValueOf[S](d.s[i])}
+		}
+		if d.keys[i].X != nil {
+			pubs[i] = ecdsa.PublicKey[T, S]{X: emulated.ValueOf[T](d.keys[i].X), Y: emulated.ValueOf[T](d.keys[i].Y)}
+		}
+	}
+	msg := emulated.ValueOf[S](d.msgHash)
+	switch {
+	case in.CommitmentHash != "" && in.PublicThreshold:
+		return &ThresholdCommitmentPublicKCircuit[T, S]{Sigs: sigs, Enabled: enabled, Msg: msg, Pubs: pubs, Commitment: d.commitment, K: in.Threshold}
+	case in.CommitmentHash != "":
+		return &ThresholdCommitmentCircuit[T, S]{Sigs: sigs, Enabled: enabled, Msg: msg, Pubs: pubs, Commitment: d.commitment}
+	case in.PublicThreshold:
+		return &ThresholdPublicKCircuit[T, S]{Sigs: sigs, Enabled: enabled, Msg: msg, Pubs: pubs, K: in.Threshold}
+	default:
+		return &ThresholdCircuit[T, S]{Sigs: sigs, Enabled: enabled, Msg: msg, Pubs: pubs}
+	}
+}
+
+// GenerateThresholdInput signs sha256(msg) with threshold of signers fresh
+// keys on curve, chosen at random, and returns the threshold circuit witness
+// input. publicThreshold selects the variants with a public threshold, commit
+// those committing to the keys over hash.
+func GenerateThresholdInput(curve Curve, msg []byte, signers, threshold int, publicThreshold, commit bool, hash FieldHash) (*ThresholdInput, error) {
+	if threshold <= 0 || threshold > signers {
+		return nil, fmt.Errorf("invalid threshold %d of %d signers", threshold, signers)
+	}
+	in := &ThresholdInput{Curve: curve.canonical(), Threshold: threshold, PublicThreshold: publicThreshold}
+	if commit {
+		in.CommitmentHash = hash.canonical()
+	}
+	// Pick the signers by drawing a random permutation
+	order := make([]int, signers)
+	for i := range order {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to draw the signers: %w", err)
+		}
+		order[i] = order[j.Int64()]
+		order[j.Int64()] = i
+	}
+	signs := make([]bool, signers)
+	for _, i := range order[:threshold] {
+		signs[i] = true
+	}
+	for i := range signers {
+		signed, err := GenerateInput(curve, msg)
+		if err != nil {
+			return nil, err
+		}
+		in.MsgHash = signed.MsgHash
+		signer := ThresholdSigner{PubX: signed.PubX, PubY: signed.PubY}
+		if signs[i] {
+			signer.R, signer.S = signed.R, signed.S
+		}
+		in.Signers = append(in.Signers, signer)
+	}
+	if commit {
+		d, err := in.decodePublic()
+		if err != nil {
+			return nil, err
+		}
+		in.Commitment = fieldHex(d.commitment)
+	}
+	return in, nil
+}
@@ -0,0 +1,110 @@
+package zkecdsa
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/consensys/gnark/test"
+)
+
+type (
+	p256Threshold        = ThresholdCircuit[emulated.P256Fp, emulated.P256Fr]
+	p256ThresholdPublicK = ThresholdPublicKCircuit[emulated.P256Fp, emulated.P256Fr]
+)
+
+// testThresholdAssignment returns the circuit of threshold of signers P-256
+// keys and an assignment where threshold keys signed, with the indices of
+// the enabled and disabled slots.
+func testThresholdAssignment(t *testing.T, signers, threshold int, publicThreshold bool) (circuit, assignment frontend.Circuit, enabled, disabled []int) {
+	t.Helper()
+	in, err := GenerateThresholdInput(P256, []byte("threshold"), signers, threshold, publicThreshold, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled := threshold
+	if publicThreshold {
+		compiled = 0
+	}
+	if circuit, err = NewThresholdCircuit(P256, signers, compiled, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if assignment, err = in.Assignment(true); err != nil {
+		t.Fatal(err)
+	}
+	for i, signer := range in.Signers {
+		if signer.R != "" {
+			enabled = append(enabled, i)
+		} else {
+			disabled = append(disabled, i)
+		}
+	}
+	return circuit, assignment, enabled, disabled
+}
+
+func TestThresholdCircuit(t *testing.T) {
+	dummy := thresholdDummy[emulated.P256Fp, emulated.P256Fr]()
+	tests := []struct {
+		name  string
+		edit  func(c *p256Threshold, enabled, disabled []int)
+		valid bool
+	}{
+		{"k signatures", func(c *p256Threshold, enabled, disabled []int) {}, true},
+		{"k-1 signatures", func(c *p256Threshold, enabled, disabled []int) {
+			c.Enabled[enabled[0]] = 0
+		}, false},
+		{"disabled slot with the dummy signature", func(c *p256Threshold, enabled, disabled []int) {
+			c.Sigs[disabled[0]] = ecdsa.Signature[emulated.P256Fr]{R: emulated.ValueOf[emulated.P256Fr](dummy.r), S: emulated.ValueOf[emulated.P256Fr](dummy.s)}
+		}, true},
+		{"enabled slot with a wrong signature", func(c *p256Threshold, enabled, disabled []int) {
+			c.Sigs[enabled[0]], c.Sigs[enabled[1]] = c.Sigs[enabled[1]], c.Sigs[enabled[0]]
+		}, false},
+		{"same key in two enabled slots", func(c *p256Threshold, enabled, disabled []int) {
+			c.Pubs[enabled[1]], c.Sigs[enabled[1]] = c.Pubs[enabled[0]], c.Sigs[enabled[0]]
+		}, false},
+		{"enable bit not boolean", func(c *p256Threshold, enabled, disabled []int) {
+			c.Enabled[enabled[0]] = 2
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			circuit, assignment, enabled, disabled := testThresholdAssignment(t, 3, 2, false)
+			tt.edit(assignment.(*p256Threshold), enabled, disabled)
+			err := test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
+
+func TestThresholdPublicKCircuit(t *testing.T) {
+	tests := []struct {
+		name  string
+		k     int
+		valid bool
+	}{
+		{"k of n", 2, true},
+		{"n of n", 3, true},
+		{"k above the signatures", 3 + 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every key signs, so only K can fail the proof
+			circuit, assignment, _, _ := testThresholdAssignment(t, 3, 3, true)
+			assignment.(*p256ThresholdPublicK).K = tt.k
+			err := test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package zkecdsa
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// ErrInvalidInput matches every error caused by a witness input rather than
+// by the artifacts or the proof system. The more specific errors below are
+// wrapped in an *InputError naming the field at fault.
+var ErrInvalidInput = errors.New("invalid input")
+
+var (
+	ErrInvalidHex       = errors.New("not valid hex")
+	ErrInvalidLength    = errors.New("wrong length")
+	ErrInvalidEncoding  = errors.New("malformed encoding")
+	ErrPointNotOnCurve  = errors.New("public key is not on curve")
+	ErrScalarOutOfRange = errors.New("signature scalar out of range")
+	ErrHighS            = errors.New("signature S is not low")
+)
+
+// InputError reports an invalid field of a witness input. It matches
+// ErrInvalidInput and unwraps to the specific error.
+type InputError struct {
+	Field string // Field at fault, such as "R" or "Signers[1].PubX"
+	Err   error  // ErrInvalidHex, ErrInvalidLength, ErrInvalidEncoding, ErrPointNotOnCurve, ErrScalarOutOfRange or ErrHighS, possibly wrapped
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Field, e.Err)
+}
+
+func (e *InputError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrInvalidInput.
+func (e *InputError) Is(target error) bool { return target == ErrInvalidInput }
+
+// invalidInput marks err as caused by the input, unless it already is.
+func invalidInput(err error) error {
+	if err == nil || errors.Is(err, ErrInvalidInput) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+}
+
+// MsgHashLen is the length of the message hash of the circuits, a SHA-256
+// digest. Longer digests must be truncated to their leftmost bytes first, as
+// ECDSA does.
+const MsgHashLen = 32
+
+// scalarLen is the maximum length of a signature scalar or key coordinate,
+// leading zero bytes being optional.
+const scalarLen = 32
+
+// decodeFixed decodes a hex field of exactly size bytes.
+func decodeFixed(name, value string, size int) ([]byte, error) {
+	b, err := decodeHex(name, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, &InputError{Field: name, Err: fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidLength, len(b), size)}
+	}
+	return b, nil
+}
+
+// decodeInt decodes a hex big-endian integer of at most scalarLen bytes.
+func decodeInt(name, value string) (*big.Int, error) {
+	b, err := decodeHex(name, value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 || len(b) > scalarLen {
+		return nil, &InputError{Field: name, Err: fmt.Errorf("%w: %d bytes, expected 1 to %d", ErrInvalidLength, len(b), scalarLen)}
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// decodeScalar decodes a signature scalar, see checkScalar.
+func decodeScalar(curve Curve, name, value string) (*big.Int, error) {
+	v, err := decodeInt(name, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScalar(curve, name, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// checkScalar checks a signature scalar is in [1, n-1] for the order n of
+// curve.
+func checkScalar(curve Curve, name string, v *big.Int) error {
+	order, err := curveOrder(curve)
+	if err != nil {
+		return err
+	}
+	if v.Sign() <= 0 || v.Cmp(order) >= 0 {
+		return &InputError{Field: name, Err: fmt.Errorf("%w: not in [1, n-1] for %s", ErrScalarOutOfRange, curve.canonical())}
+	}
+	return nil
+}
+
+// decodePoint decodes the coordinates of a public key, checking it is a
+// point of curve. prefix is prepended to the PubX and PubY field names.
+func decodePoint(curve Curve, prefix, x, y string) (*big.Int, *big.Int, error) {
+	pubX, err := decodeInt(prefix+"PubX", x)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubY, err := decodeInt(prefix+"PubY", y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := (PublicKey{X: pubX, Y: pubY}).check(curve); err != nil {
+		if errors.Is(err, ErrPointNotOnCurve) {
+			return nil, nil, &InputError{Field: prefix + "PubX/PubY", Err: err}
+		}
+		return nil, nil, err
+	}
+	return pubX, pubY, nil
+}
+
+// decodeDERSignature decodes the hex of an ASN.1 DER signature, checking
+// its scalars with checkScalar.
+func decodeDERSignature(curve Curve, name, value string) (*big.Int, *big.Int, error) {
+	der, err := decodeHex(name, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, s, err := parseDERSignature(der)
+	if err != nil {
+		return nil, nil, &InputError{Field: name, Err: fmt.Errorf("%w: %v", ErrInvalidEncoding, err)}
+	}
+	if err := checkScalar(curve, name+" R", r); err != nil {
+		return nil, nil, err
+	}
+	if err := checkScalar(curve, name+" S", s); err != nil {
+		return nil, nil, err
+	}
+	return r, s, nil
+}
+
+// decodePublicKey parses an encoded public key on curve, see ParsePublicKey.
+func decodePublicKey(curve Curve, name, value string) (*big.Int, *big.Int, error) {
+	key, err := ParsePublicKey(curve.canonical(), value)
+	if err != nil {
+		return nil, nil, &InputError{Field: name, Err: err}
+	}
+	return key.X, key.Y, nil
+}
+
+// curveOrder returns the order n of the group of curve.
+func curveOrder(curve Curve) (*big.Int, error) {
+	switch curve.canonical() {
+	case P256:
+		return elliptic.P256().Params().N, nil
+	case Secp256k1:
+		return fr.Modulus(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+// ValidationPolicy holds the optional checks of ValidateInput. The structural
+// checks, hex, lengths, scalar ranges and points on the curve, always apply.
+type ValidationPolicy struct {
+	// RequireLowS rejects signatures with S above n/2, the malleable twin of
+	// a low-S signature, as Bitcoin and Ethereum do.
+	RequireLowS bool
+}
+
+// ValidateInput checks every field of input up front, reporting an
+// *InputError for the first one at fault, so a bad input does not surface
+// as an opaque solver failure while proving. Inputs without a curve are
+// taken to be on P256.
+func ValidateInput(input Input, policy ValidationPolicy) error {
+	if _, err := input.Assignment(false); err != nil {
+		return invalidInput(err)
+	}
+	if policy.RequireLowS {
+		return checkLowS(input)
+	}
+	return nil
+}
+
+// checkLowS reports an *InputError wrapping ErrHighS if a signature of input
+// has S above n/2.
+func checkLowS(input Input) error {
+	order, err := curveOrder(input.curve())
+	if err != nil {
+		return err
+	}
+	half := new(big.Int).Rsh(order, 1)
+	sigS, err := input.signatureS()
+	if err != nil {
+		return invalidInput(err)
+	}
+	for i, s := range sigS {
+		if s != nil && s.Cmp(half) > 0 {
+			field := "S"
+			if len(sigS) > 1 {
+				field = fmt.Sprintf("signature %d S", i)
+			}
+			return &InputError{Field: field, Err: ErrHighS}
+		}
+	}
+	return nil
+}
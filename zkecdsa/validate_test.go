@@ -0,0 +1,158 @@
+package zkecdsa
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// lowAndHighS returns a copy of input with a low S and one with its high
+// twin n-S, both valid signatures.
+func lowAndHighS(t *testing.T, input *ProveInputEcdsa) (low, high *ProveInputEcdsa) {
+	t.Helper()
+	order, err := curveOrder(input.Curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := new(big.Int).SetString(input.S, 16)
+	if !ok {
+		t.Fatalf("S %q is not hex", input.S)
+	}
+	twin := new(big.Int).Sub(order, s)
+	if s.Cmp(twin) > 0 {
+		s, twin = twin, s
+	}
+	low, high = new(ProveInputEcdsa), new(ProveInputEcdsa)
+	*low, *high = *input, *input
+	low.S, high.S = fmt.Sprintf("%064x", s), fmt.Sprintf("%064x", twin)
+	return low, high
+}
+
+func TestValidateInput(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(string(curve), func(t *testing.T) {
+			valid, err := GenerateInput(curve, []byte("validate"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lowS, highS := lowAndHighS(t, valid)
+			order, _ := curveOrder(curve)
+			tests := []struct {
+				name   string
+				edit   func(in *ProveInputEcdsa)
+				policy ValidationPolicy
+				field  string
+				want   error // nil for a valid input
+			}{
+				{"valid", func(in *ProveInputEcdsa) {}, ValidationPolicy{}, "", nil},
+				{"message hash not hex", func(in *ProveInputEcdsa) { in.MsgHash = "zz" + in.MsgHash[2:] }, ValidationPolicy{}, "MsgHash", ErrInvalidHex},
+				{"short message hash", func(in *ProveInputEcdsa) { in.MsgHash = in.MsgHash[2:] }, ValidationPolicy{}, "MsgHash", ErrInvalidLength},
+				{"empty R", func(in *ProveInputEcdsa) { in.R = "" }, ValidationPolicy{}, "R", ErrInvalidLength},
+				{"long S", func(in *ProveInputEcdsa) { in.S = "01" + in.S }, ValidationPolicy{}, "S", ErrInvalidLength},
+				{"zero R", func(in *ProveInputEcdsa) { in.R = "00" }, ValidationPolicy{}, "R", ErrScalarOutOfRange},
+				{"S equal to n", func(in *ProveInputEcdsa) { in.S = fmt.Sprintf("%064x", order) }, ValidationPolicy{}, "S", ErrScalarOutOfRange},
+				{"key off the curve", func(in *ProveInputEcdsa) { in.PubY = in.PubX }, ValidationPolicy{}, "PubX/PubY", ErrPointNotOnCurve},
+				{"low S required", func(in *ProveInputEcdsa) { *in = *lowS }, ValidationPolicy{RequireLowS: true}, "", nil},
+				{"high S allowed", func(in *ProveInputEcdsa) { *in = *highS }, ValidationPolicy{}, "", nil},
+				{"high S rejected", func(in *ProveInputEcdsa) { *in = *highS }, ValidationPolicy{RequireLowS: true}, "S", ErrHighS},
+				{"signature and R", func(in *ProveInputEcdsa) { in.Signature = "3006020101020101" }, ValidationPolicy{}, "Signature", ErrInvalidEncoding},
+				{"malformed DER", func(in *ProveInputEcdsa) { in.R, in.S, in.Signature = "", "", "3006" }, ValidationPolicy{}, "Signature", ErrInvalidEncoding},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					input := *valid
+					tt.edit(&input)
+					err := ValidateInput(&input, tt.policy)
+					if tt.want == nil {
+						if err != nil {
+							t.Fatalf("ValidateInput = %v, want nil", err)
+						}
+						return
+					}
+					if !errors.Is(err, tt.want) || !errors.Is(err, ErrInvalidInput) {
+						t.Fatalf("ValidateInput = %v, want %v and ErrInvalidInput", err, tt.want)
+					}
+					var inputErr *InputError
+					if !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+						t.Errorf("ValidateInput = %v, want an *InputError on %s", err, tt.field)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestValidateInputDERSignature(t *testing.T) {
+	valid, err := GenerateInput(P256, []byte("validate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := new(big.Int).SetString(valid.R, 16)
+	s, _ := new(big.Int).SetString(valid.S, 16)
+	der, err := marshalDERSignature(r, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := *valid
+	input.R, input.S, input.Signature = "", "", hex.EncodeToString(der)
+	if err := ValidateInput(&input, ValidationPolicy{}); err != nil {
+		t.Fatalf("ValidateInput of the DER signature = %v", err)
+	}
+	sigS, err := input.signatureS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigS) != 1 || sigS[0].Cmp(s) != 0 {
+		t.Errorf("signatureS = %v, want [%x]", sigS, s)
+	}
+}
+
+func TestValidateWebAuthnInput(t *testing.T) {
+	valid, err := GenerateWebAuthnInput(P256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	otherChallenge := b64(make([]byte, ChallengeLen))
+	tests := []struct {
+		name  string
+		edit  func(in *WebAuthnInput)
+		field string
+		want  error // nil for a valid input
+	}{
+		{"valid", func(in *WebAuthnInput) {}, "", nil},
+		{"padded base64url", func(in *WebAuthnInput) { in.Signature += "==" }, "", nil},
+		{"authenticator data not base64url", func(in *WebAuthnInput) { in.AuthenticatorData = "+/" }, "AuthenticatorData", ErrInvalidEncoding},
+		{"short authenticator data", func(in *WebAuthnInput) { in.AuthenticatorData = b64(make([]byte, 36)) }, "AuthenticatorData", ErrInvalidLength},
+		{"long authenticator data", func(in *WebAuthnInput) { in.AuthenticatorData = b64(make([]byte, MaxAuthenticatorDataLen+1)) }, "AuthenticatorData", ErrInvalidLength},
+		{"client data not JSON", func(in *WebAuthnInput) { in.ClientDataJSON = b64([]byte("{")) }, "ClientDataJSON", ErrInvalidEncoding},
+		{"long client data", func(in *WebAuthnInput) { in.ClientDataJSON = b64(make([]byte, MaxClientDataJSONLen+1)) }, "ClientDataJSON", ErrInvalidLength},
+		{"short client data challenge", func(in *WebAuthnInput) { in.ClientDataJSON = b64([]byte(`{"challenge":"AAAA"}`)) }, "ClientDataJSON.challenge", ErrInvalidLength},
+		{"escaped client data challenge", func(in *WebAuthnInput) {
+			in.ClientDataJSON = b64([]byte(`{"challenge":"\u0041` + otherChallenge[1:] + `"}`))
+		}, "ClientDataJSON", ErrInvalidEncoding},
+		{"other challenge", func(in *WebAuthnInput) { in.Challenge = otherChallenge }, "ClientDataJSON.challenge", ErrInvalidEncoding},
+		{"short challenge", func(in *WebAuthnInput) { in.Challenge = "AAAA" }, "Challenge", ErrInvalidLength},
+		{"malformed signature", func(in *WebAuthnInput) { in.Signature = b64([]byte{0x30, 0x00}) }, "Signature", ErrInvalidEncoding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := *valid
+			tt.edit(&input)
+			err := ValidateInput(&input, ValidationPolicy{})
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateInput = %v, want nil", err)
+				}
+				return
+			}
+			var inputErr *InputError
+			if !errors.Is(err, tt.want) || !errors.As(err, &inputErr) || inputErr.Field != tt.field {
+				t.Fatalf("ValidateInput = %v, want %v on %s", err, tt.want, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package zkecdsa
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/cmp"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// Sizes of the WebAuthn circuit. The authenticator data and client data are
+// hashed up to their actual length inside fixed-size buffers.
+const (
+	MaxAuthenticatorDataLen = 64  // rpIdHash, flags and signCount take 37 bytes, the rest is for extensions
+	MaxClientDataJSONLen    = 320 // Browsers stay well under this
+	minAuthenticatorDataLen = 37
+	ChallengeLen            = 32 // Challenges are 32 random bytes
+	challengeBase64Len      = 43 // base64url of ChallengeLen bytes, without padding
+)
+
+// challengeKey precedes the challenge in clientDataJSON. The serialization of
+// clientDataJSON is fixed by the WebAuthn spec, without whitespace, and a quote
+// inside a JSON string is escaped, so the key cannot be forged in a value.
+const challengeKey = `"challenge":"`
+
+// WebAuthnCircuit verifies a WebAuthn assertion: the ECDSA signature of the
+// credential key over sha256(authenticatorData || sha256(clientDataJSON)),
+// with the challenge in clientDataJSON bound to a public input. The challenge
+// and the credential public key are public, the assertion itself is private.
+type WebAuthnCircuit[T, S emulated.FieldParams] struct {
+	AuthenticatorData    [MaxAuthenticatorDataLen]uints.U8
+	AuthenticatorDataLen frontend.Variable
+	ClientDataJSON       [MaxClientDataJSONLen]uints.U8
+	ClientDataJSONLen    frontend.Variable
+	ChallengeIndex       frontend.Variable // Offset of the base64url challenge in ClientDataJSON
+
+	Sig       ecdsa.Signature[S]
+	Challenge [2]frontend.Variable  `gnark:",public"` // Big-endian 128-bit halves of the challenge
+	Pub       ecdsa.PublicKey[T, S] `gnark:",public"`
+}
+
+func (c *WebAuthnCircuit[T, S]) Define(api frontend.API) error {
+	bf, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	// Range check the private bytes, the hash gadgets take them as given
+	for i := range c.AuthenticatorData {
+		c.AuthenticatorData[i] = bf.ByteValueOf(c.AuthenticatorData[i].Val)
+	}
+	for i := range c.ClientDataJSON {
+		c.ClientDataJSON[i] = bf.ByteValueOf(c.ClientDataJSON[i].Val)
+	}
+	// The signed data always starts with minAuthenticatorDataLen bytes of
+	// authenticator data, see messageHash
+	api.AssertIsLessOrEqual(minAuthenticatorDataLen, c.AuthenticatorDataLen)
+	api.AssertIsLessOrEqual(c.AuthenticatorDataLen, MaxAuthenticatorDataLen)
+	api.AssertIsLessOrEqual(c.ClientDataJSONLen, MaxClientDataJSONLen)
+
+	c.checkChallenge(api)
+	msg, err := c.messageHash(api)
+	if err != nil {
+		return err
+	}
+	scalars, err := emulated.NewField[S](api)
+	if err != nil {
+		return err
+	}
+	// The hash is big-endian, FromBits takes the least significant bit first
+	bits := make([]frontend.Variable, 0, 8*len(msg))
+	for i := len(msg) - 1; i >= 0; i-- {
+		bits = append(bits, api.ToBinary(msg[i].Val, 8)...)
+	}
+	signature := EcdsaCircuit[T, S]{Sig: c.Sig, Msg: *scalars.FromBits(bits...), Pub: c.Pub}
+	return signature.Define(api)
+}
+
+// messageHash computes sha256(authenticatorData || sha256(clientDataJSON)).
+func (c *WebAuthnCircuit[T, S]) messageHash(api frontend.API) ([]uints.U8, error) {
+	clientDataHasher, err := sha2.New(api)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHasher.Write(c.ClientDataJSON[:])
+	clientDataHash := clientDataHasher.FixedLengthSum(c.ClientDataJSONLen)
+
+	// The client data hash starts right after the authenticator data, at a
+	// position only known at proving time: each byte is either authenticator
+	// data or looked up in the client data hash
+	hashTable := logderivlookup.New(api)
+	for i := range clientDataHash {
+		hashTable.Insert(clientDataHash[i].Val)
+	}
+	comparator := cmp.NewBoundedComparator(api, big.NewInt(MaxAuthenticatorDataLen+sha256.Size+1), false)
+	signedData := make([]uints.U8, MaxAuthenticatorDataLen+sha256.Size)
+	copy(signedData, c.AuthenticatorData[:minAuthenticatorDataLen])
+	for i := minAuthenticatorDataLen; i < len(signedData); i++ {
+		inAuthData := comparator.IsLess(i, c.AuthenticatorDataLen)
+		inHash := api.Sub(1, inAuthData)
+		inHash = api.Mul(inHash, comparator.IsLess(i, api.Add(c.AuthenticatorDataLen, sha256.Size)))
+		hashByte := hashTable.Lookup(api.Select(inHash, api.Sub(i, c.AuthenticatorDataLen), 0))[0]
+		var authByte frontend.Variable = 0
+		if i < MaxAuthenticatorDataLen {
+			authByte = c.AuthenticatorData[i].Val
+		}
+		signedData[i] = uints.U8{Val: api.Select(inAuthData, authByte, api.Mul(inHash, hashByte))}
+	}
+
+	signedDataHasher, err := sha2.New(api, hash.WithMinimalLength(minAuthenticatorDataLen+sha256.Size))
+	if err != nil {
+		return nil, err
+	}
+	signedDataHasher.Write(signedData)
+	return signedDataHasher.FixedLengthSum(api.Add(c.AuthenticatorDataLen, sha256.Size)), nil
+}
+
+// checkChallenge asserts that clientDataJSON holds "challenge":"<challenge>"
+// at ChallengeIndex, the challenge being base64url encoded from the public input.
+func (c *WebAuthnCircuit[T, S]) checkChallenge(api frontend.API) {
+	// Big-endian bits of the challenge, zero padded to whole base64 digits
+	bits := make([]frontend.Variable, 0, 6*challengeBase64Len)
+	for _, half := range c.Challenge {
+		halfBits := api.ToBinary(half, 128)
+		for i := len(halfBits) - 1; i >= 0; i-- {
+			bits = append(bits, halfBits[i])
+		}
+	}
+	for len(bits) < 6*challengeBase64Len {
+		bits = append(bits, 0)
+	}
+
+	expected := make([]frontend.Variable, 0, len(challengeKey)+challengeBase64Len+1)
+	for _, b := range []byte(challengeKey) {
+		expected = append(expected, int(b))
+	}
+	comparator := cmp.NewBoundedComparator(api, big.NewInt(64), false)
+	for i := 0; i < challengeBase64Len; i++ {
+		digit := api.FromBinary(bits[6*i+5], bits[6*i+4], bits[6*i+3], bits[6*i+2], bits[6*i+1], bits[6*i])
+		expected = append(expected, base64URLChar(api, comparator, digit))
+	}
+	expected = append(expected, int('"'))
+
+	// The whole match lies within the hashed part of the client data
+	start := api.Sub(c.ChallengeIndex, len(challengeKey))
+	api.AssertIsLessOrEqual(api.Add(start, len(expected)), c.ClientDataJSONLen)
+	clientDataTable := logderivlookup.New(api)
+	for i := range c.ClientDataJSON {
+		clientDataTable.Insert(c.ClientDataJSON[i].Val)
+	}
+	indices := make([]frontend.Variable, len(expected))
+	for i := range indices {
+		indices[i] = api.Add(start, i)
+	}
+	for i, b := range clientDataTable.Lookup(indices...) {
+		api.AssertIsEqual(b, expected[i])
+	}
+}
+
+// base64URLChar maps a 6-bit digit to its base64url character.
+func base64URLChar(api frontend.API, comparator *cmp.BoundedComparator, digit frontend.Variable) frontend.Variable {
+	char := api.Select(api.IsZero(api.Sub(digit, 62)), int('-'), int('_'))
+	char = api.Select(comparator.IsLess(digit, 62), api.Sub(digit, 52-int('0')), char)
+	char = api.Select(comparator.IsLess(digit, 52), api.Add(digit, int('a')-26), char)
+	return api.Select(comparator.IsLess(digit, 26), api.Add(digit, int('A')), char)
+}
+
+// NewWebAuthnCircuit returns the empty WebAuthn circuit definition to compile for curve.
+func NewWebAuthnCircuit(curve Curve) (frontend.Circuit, error) {
+	switch curve.canonical() {
+	case P256:
+		return &WebAuthnCircuit[emulated.P256Fp, emulated.P256Fr]{}, nil
+	case Secp256k1:
+		return &WebAuthnCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
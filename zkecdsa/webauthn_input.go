@@ -0,0 +1,277 @@
+package zkecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// WebAuthnInput struct for JSON serialization of WebAuthn witness inputs. The
+// assertion fields are base64url, as a browser's PublicKeyCredential hands
+// them over once JSON encoded.
+type WebAuthnInput struct {
+	AuthenticatorData string `json:"authenticatorData,omitempty"` // base64url of the authenticator data
+	ClientDataJSON    string `json:"clientDataJSON,omitempty"`    // base64url of the client data JSON
+	Signature         string `json:"signature,omitempty"`         // base64url of the ASN.1 DER signature
+	Challenge         string `json:"challenge,omitempty"`         // base64url of the challenge, taken from ClientDataJSON if empty
+	PubX              string `json:"pubX"`                        // Hex string of public key X
+	PubY              string `json:"pubY"`                        // Hex string of public key Y
+	Curve             Curve  `json:"curve,omitempty"`             // Credential key curve, P256 if empty
+}
+
+// NewWebAuthnInput builds the witness input of a raw assertion.
+func NewWebAuthnInput(authenticatorData, clientDataJSON, signature []byte, pubX, pubY string, curve Curve) *WebAuthnInput {
+	return &WebAuthnInput{
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authenticatorData),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+		PubX:              pubX,
+		PubY:              pubY,
+		Curve:             curve,
+	}
+}
+
+// Circuit returns CircuitWebAuthn.
+func (in *WebAuthnInput) Circuit() CircuitType { return CircuitWebAuthn }
+
+func (in *WebAuthnInput) curve() Curve { return in.Curve }
+
+func (in *WebAuthnInput) withCurve(curve Curve) Input {
+	withCurve := *in
+	withCurve.Curve = curve
+	return &withCurve
+}
+
+// decodedWebAuthn holds the values of a WebAuthnInput once decoded.
+type decodedWebAuthn struct {
+	authenticatorData []byte
+	clientDataJSON    []byte
+	challengeIndex    int
+	challenge         []byte
+	r, s              *big.Int
+	pubX, pubY        *big.Int
+}
+
+// decodeBase64URL decodes a single base64url field, padded or not, naming it
+// in the error.
+func decodeBase64URL(name, value string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, &InputError{Field: name, Err: fmt.Errorf("%w: base64url: %v", ErrInvalidEncoding, err)}
+	}
+	return b, nil
+}
+
+// decodePublic decodes the challenge and the public key.
+func (in *WebAuthnInput) decodePublic() (*decodedWebAuthn, error) {
+	pubX, pubY, err := decodePoint(in.Curve, "", in.PubX, in.PubY)
+	if err != nil {
+		return nil, err
+	}
+	d := &decodedWebAuthn{pubX: pubX, pubY: pubY}
+	if in.Challenge == "" {
+		if err := in.decodeClientData(d); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if d.challenge, err = decodeBase64URL("Challenge", in.Challenge); err != nil {
+		return nil, err
+	}
+	if len(d.challenge) != ChallengeLen {
+		return nil, &InputError{Field: "Challenge", Err: fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidLength, len(d.challenge), ChallengeLen)}
+	}
+	return d, nil
+}
+
+// decodeClientData decodes the client data JSON into d and locates the challenge in it.
+func (in *WebAuthnInput) decodeClientData(d *decodedWebAuthn) error {
+	clientDataJSON, err := decodeBase64URL("ClientDataJSON", in.ClientDataJSON)
+	if err != nil {
+		return err
+	}
+	if len(clientDataJSON) > MaxClientDataJSONLen {
+		return &InputError{Field: "ClientDataJSON", Err: fmt.Errorf("%w: %d bytes, at most %d are supported", ErrInvalidLength, len(clientDataJSON), MaxClientDataJSONLen)}
+	}
+	var clientData struct {
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return &InputError{Field: "ClientDataJSON", Err: fmt.Errorf("%w: %v", ErrInvalidEncoding, err)}
+	}
+	challenge, err := decodeBase64URL("ClientDataJSON.challenge", clientData.Challenge)
+	if err != nil {
+		return err
+	}
+	if len(challenge) != ChallengeLen {
+		return &InputError{Field: "ClientDataJSON.challenge", Err: fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidLength, len(challenge), ChallengeLen)}
+	}
+	if d.challenge != nil && string(d.challenge) != string(challenge) {
+		return &InputError{Field: "ClientDataJSON.challenge", Err: fmt.Errorf("%w: does not match Challenge", ErrInvalidEncoding)}
+	}
+
+	// The circuit matches the challenge as the browser serializes it, unpadded
+	match := challengeKey + base64.RawURLEncoding.EncodeToString(challenge) + `"`
+	index := strings.Index(string(clientDataJSON), match)
+	if index < 0 {
+		return &InputError{Field: "ClientDataJSON", Err: fmt.Errorf("%w: does not hold the challenge as %s", ErrInvalidEncoding, match)}
+	}
+	d.clientDataJSON = clientDataJSON
+	d.challengeIndex = index + len(challengeKey)
+	d.challenge = challenge
+	return nil
+}
+
+// decode decodes every field of the input.
+func (in *WebAuthnInput) decode() (*decodedWebAuthn, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	if d.clientDataJSON == nil {
+		if err := in.decodeClientData(d); err != nil {
+			return nil, err
+		}
+	}
+	if d.authenticatorData, err = decodeBase64URL("AuthenticatorData", in.AuthenticatorData); err != nil {
+		return nil, err
+	}
+	if n := len(d.authenticatorData); n < minAuthenticatorDataLen || n > MaxAuthenticatorDataLen {
+		return nil, &InputError{Field: "AuthenticatorData", Err: fmt.Errorf("%w: %d bytes, expected %d to %d", ErrInvalidLength, n, minAuthenticatorDataLen, MaxAuthenticatorDataLen)}
+	}
+	signature, err := decodeBase64URL("Signature", in.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if d.r, d.s, err = parseDERSignature(signature); err != nil {
+		return nil, &InputError{Field: "Signature", Err: fmt.Errorf("%w: %v", ErrInvalidEncoding, err)}
+	}
+	if err := checkScalar(in.Curve, "Signature R", d.r); err != nil {
+		return nil, err
+	}
+	if err := checkScalar(in.Curve, "Signature S", d.s); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (in *WebAuthnInput) signatureS() ([]*big.Int, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	return []*big.Int{d.s}, nil
+}
+
+// Assignment builds the full WebAuthnCircuit assignment over the input curve.
+// The circuit has a single variant, so publicInputs is ignored.
+func (in *WebAuthnInput) Assignment(publicInputs bool) (frontend.Circuit, error) {
+	d, err := in.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return webAuthnAssignment[emulated.P256Fp, emulated.P256Fr](d), nil
+	case Secp256k1:
+		return webAuthnAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+// PublicAssignment builds the WebAuthnCircuit assignment from the challenge
+// and public key alone. The assertion is only read if Challenge is empty.
+func (in *WebAuthnInput) PublicAssignment() (frontend.Circuit, error) {
+	d, err := in.decodePublic()
+	if err != nil {
+		return nil, err
+	}
+	switch in.Curve.canonical() {
+	case P256:
+		return webAuthnPublicAssignment[emulated.P256Fp, emulated.P256Fr](d), nil
+	case Secp256k1:
+		return webAuthnPublicAssignment[emulated.Secp256k1Fp, emulated.Secp256k1Fr](d), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", in.Curve)
+	}
+}
+
+func webAuthnAssignment[T, S emulated.FieldParams](d *decodedWebAuthn) frontend.Circuit {
+	c := webAuthnPublicAssignment[T, S](d)
+	for i := range c.AuthenticatorData {
+		c.AuthenticatorData[i] = uints.NewU8(0)
+	}
+	for i, b := range d.authenticatorData {
+		c.AuthenticatorData[i] = uints.NewU8(b)
+	}
+	for i := range c.ClientDataJSON {
+		c.ClientDataJSON[i] = uints.NewU8(0)
+	}
+	for i, b := range d.clientDataJSON {
+		c.ClientDataJSON[i] = uints.NewU8(b)
+	}
+	c.AuthenticatorDataLen = len(d.authenticatorData)
+	c.ClientDataJSONLen = len(d.clientDataJSON)
+	c.ChallengeIndex = d.challengeIndex
+	c.Sig = ecdsa.Signature[S]{
+		R: emulated.ValueOf[S](d.r),
+		S: emulated.ValueOf[S](d.s),
+	}
+	return c
+}
+
+func webAuthnPublicAssignment[T, S emulated.FieldParams](d *decodedWebAuthn) *WebAuthnCircuit[T, S] {
+	return &WebAuthnCircuit[T, S]{
+		Challenge: [2]frontend.Variable{
+			new(big.Int).SetBytes(d.challenge[:ChallengeLen/2]),
+			new(big.Int).SetBytes(d.challenge[ChallengeLen/2:]),
+		},
+		Pub: ecdsa.PublicKey[T, S]{
+			X: emulated.ValueOf[T](d.pubX),
+			Y: emulated.ValueOf[T](d.pubY),
+		},
+	}
+}
+
+// GenerateWebAuthnInput builds an assertion over a random challenge, as an
+// authenticator would for https://example.com, signed with a fresh key on curve.
+func GenerateWebAuthnInput(curve Curve) (*WebAuthnInput, error) {
+	challenge := make([]byte, ChallengeLen)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, fmt.Errorf("failed to generate random challenge: %w", err)
+	}
+
+	// rpIdHash, flags (user present and verified) and signCount
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	authenticatorData := append(rpIDHash[:], 0x05)
+	authenticatorData = binary.BigEndian.AppendUint32(authenticatorData, 1)
+	clientDataJSON := []byte(`{"type":"webauthn.get","challenge":"` + base64.RawURLEncoding.EncodeToString(challenge) +
+		`","origin":"https://example.com","crossOrigin":false}`)
+
+	// The authenticator signs authenticatorData || sha256(clientDataJSON)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed, err := GenerateInput(curve, append(authenticatorData, clientDataHash[:]...))
+	if err != nil {
+		return nil, err
+	}
+	d, err := signed.decode()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := marshalDERSignature(d.r, d.s)
+	if err != nil {
+		return nil, err
+	}
+	return NewWebAuthnInput(authenticatorData, clientDataJSON, signature, signed.PubX, signed.PubY, signed.Curve), nil
+}
@@ -0,0 +1,145 @@
+package zkecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type p256WebAuthn = WebAuthnCircuit[emulated.P256Fp, emulated.P256Fr]
+
+// testWebAuthnAssignment returns the assignment of a P-256 assertion over
+// challenge signing the first authLen bytes of authenticatorData and the
+// hash of the first clientDataLen bytes of clientDataJSON, the rest of both
+// being left in the buffers.
+func testWebAuthnAssignment(t *testing.T, authenticatorData []byte, authLen int, clientDataJSON []byte, clientDataLen int, challenge []byte) *p256WebAuthn {
+	t.Helper()
+	clientDataHash := sha256.Sum256(clientDataJSON[:clientDataLen])
+	signed, err := GenerateInput(P256, append(append([]byte(nil), authenticatorData[:authLen]...), clientDataHash[:]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := signed.decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := strings.Index(string(clientDataJSON), challengeKey+base64.RawURLEncoding.EncodeToString(challenge)+`"`)
+	if index < 0 {
+		t.Fatal("client data JSON does not hold the challenge")
+	}
+	c := webAuthnAssignment[emulated.P256Fp, emulated.P256Fr](&decodedWebAuthn{
+		authenticatorData: authenticatorData,
+		clientDataJSON:    clientDataJSON,
+		challengeIndex:    index + len(challengeKey),
+		challenge:         challenge,
+		r:                 d.r,
+		s:                 d.s,
+		pubX:              d.pubX,
+		pubY:              d.pubY,
+	}).(*p256WebAuthn)
+	c.AuthenticatorDataLen, c.ClientDataJSONLen = authLen, clientDataLen
+	return c
+}
+
+func TestWebAuthnCircuit(t *testing.T) {
+	challenge := make([]byte, ChallengeLen)
+	if _, err := rand.Read(challenge); err != nil {
+		t.Fatal(err)
+	}
+	encodedChallenge := base64.RawURLEncoding.EncodeToString(challenge)
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	authenticatorData := binary.BigEndian.AppendUint32(append(rpIDHash[:], 0x05), 1)
+	withExtensions := func(n int) []byte {
+		extended := append([]byte(nil), authenticatorData...)
+		extended[32] |= 0x80 // Extension data included
+		for len(extended) < minAuthenticatorDataLen+n {
+			extended = append(extended, byte(len(extended)))
+		}
+		return extended
+	}
+	clientDataJSON := []byte(`{"type":"webauthn.get","challenge":"` + encodedChallenge + `","origin":"https://example.com","crossOrigin":false}`)
+	// The challenge follows the hashed part of the client data
+	signedClientData := `{"type":"webauthn.get","origin":"https://example.com"}`
+	trailingChallenge := []byte(signedClientData + `"challenge":"` + encodedChallenge + `"`)
+	// 36 bytes of authenticator data followed by the first byte of the client
+	// data hash: the signed data of a 37-byte AuthenticatorData in all but its
+	// length
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	shortAuthenticatorData := append(append([]byte(nil), authenticatorData[:minAuthenticatorDataLen-1]...), clientDataHash[0])
+
+	tests := []struct {
+		name              string
+		authenticatorData []byte
+		authLen           int
+		clientDataJSON    []byte
+		clientDataLen     int
+		edit              func(c *p256WebAuthn)
+		valid             bool
+	}{
+		{"assertion", authenticatorData, minAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), nil, true},
+		{"assertion with extensions", withExtensions(13), minAuthenticatorDataLen + 13, clientDataJSON, len(clientDataJSON), nil, true},
+		{"assertion with the longest extensions", withExtensions(MaxAuthenticatorDataLen - minAuthenticatorDataLen), MaxAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), nil, true},
+		{"wrong challenge", authenticatorData, minAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), func(c *p256WebAuthn) {
+			c.Challenge[1] = new(big.Int).Add(c.Challenge[1].(*big.Int), big.NewInt(1))
+		}, false},
+		{"challenge index one byte after", authenticatorData, minAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), func(c *p256WebAuthn) {
+			c.ChallengeIndex = c.ChallengeIndex.(int) + 1
+		}, false},
+		{"challenge index one byte before", authenticatorData, minAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), func(c *p256WebAuthn) {
+			c.ChallengeIndex = c.ChallengeIndex.(int) - 1
+		}, false},
+		{"authenticator data below 37 bytes", shortAuthenticatorData, minAuthenticatorDataLen - 1, clientDataJSON, len(clientDataJSON), nil, false},
+		{"authenticator data above 64 bytes", withExtensions(MaxAuthenticatorDataLen - minAuthenticatorDataLen), MaxAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), func(c *p256WebAuthn) {
+			c.AuthenticatorDataLen = MaxAuthenticatorDataLen + 1
+		}, false},
+		{"challenge past the client data", authenticatorData, minAuthenticatorDataLen, trailingChallenge, len(signedClientData), nil, false},
+		{"client data length off by one", authenticatorData, minAuthenticatorDataLen, clientDataJSON, len(clientDataJSON), func(c *p256WebAuthn) {
+			c.ClientDataJSONLen = len(clientDataJSON) - 1
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := testWebAuthnAssignment(t, tt.authenticatorData, tt.authLen, tt.clientDataJSON, tt.clientDataLen, challenge)
+			if tt.edit != nil {
+				tt.edit(assignment)
+			}
+			err := test.IsSolved(&p256WebAuthn{}, assignment, ecc.BN254.ScalarField())
+			if tt.valid && err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("IsSolved succeeded")
+			}
+		})
+	}
+}
+
+func TestWebAuthnGeneratedInput(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(string(curve), func(t *testing.T) {
+			input, err := GenerateWebAuthnInput(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			circuit, err := NewWebAuthnCircuit(curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assignment, err := input.Assignment(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()); err != nil {
+				t.Fatalf("IsSolved = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// Package zkecdsaclient is a client of the Prover gRPC service that zkecdsa
+// serve -grpc-addr runs, taking and returning the types of the zkecdsa
+// package:
+//
+//	c, err := zkecdsaclient.Dial("127.0.0.1:9090")
+//	...
+//	defer c.Close()
+//	result, err := c.Prove(ctx, &zkecdsa.ProveInputEcdsa{MsgHash: ..., R: ..., S: ..., PubX: ..., PubY: ...})
+//	...
+//	err = c.Verify(ctx, result.Proof, result.PublicWitness)
+//
+// Errors of the server match the sentinel errors of the zkecdsa package with
+// errors.Is, as local proving errors do.
+package zkecdsaclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+	"github.com/ZKNoxHQ/GnarkPlayground/zkecdsa/zkecdsapb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// ErrUnavailable reports a server with every proof and queue slot taken, or
+// shutting down: the call can be retried later.
+var ErrUnavailable = errors.New("prover unavailable")
+
+// Error is an error reported by the server. It matches the sentinel errors
+// of the zkecdsa package for its kind with errors.Is.
+type Error struct {
+	Kind    string // Class of the error: signature_invalid, invalid_input, proving_failed, verification_failed, unavailable, not_found or internal
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is a sentinel error of the kind of e.
+func (e *Error) Is(target error) bool {
+	switch e.Kind {
+	case "signature_invalid":
+		return target == zkecdsa.ErrSignatureInvalid || target == zkecdsa.ErrInvalidInput
+	case "invalid_input":
+		return target == zkecdsa.ErrInvalidInput
+	case "proving_failed":
+		return target == zkecdsa.ErrProvingFailed
+	case "verification_failed":
+		return target == zkecdsa.ErrVerificationFailed
+	case "unavailable":
+		return target == ErrUnavailable
+	}
+	return false
+}
+
+// Result is a proof computed by the server.
+type Result struct {
+	ID            string        // ID of the request, for ProveStream
+	Proof         []byte        // See UnmarshalProof
+	PublicWitness []byte        // See UnmarshalPublicWitness
+	ProveTime     time.Duration // Time spent proving, queueing excluded
+	Err           error         // ProveStream only: why the input failed
+}
+
+// Client calls a Prover service. It is safe for concurrent use.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  zkecdsapb.ProverClient
+}
+
+// Dial returns a client of the service at target, e.g. "127.0.0.1:9090".
+// Without options the connection is not encrypted, as suits the localhost
+// service zkecdsa serve runs.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, rpc: zkecdsapb.NewProverClient(conn)}, nil
+}
+
+// Close closes the connection of c.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Prove proves input with the artifacts of the server, waiting for the
+// proof. A server with every proof and queue slot taken fails with
+// ErrUnavailable.
+func (c *Client) Prove(ctx context.Context, input zkecdsa.Input) (*Result, error) {
+	req, err := proveRequest("", input)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.rpc.Prove(ctx, req)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return result(resp), nil
+}
+
+// Verify verifies proof against publicWitness, returning an error matching
+// zkecdsa.ErrVerificationFailed if it does not hold.
+func (c *Client) Verify(ctx context.Context, proof, publicWitness []byte) error {
+	return c.verify(ctx, &zkecdsapb.VerifyRequest{
+		Proof:   proof,
+		Against: &zkecdsapb.VerifyRequest_PublicWitness{PublicWitness: publicWitness},
+	})
+}
+
+// VerifyWithPublicInputs verifies proof against the public values of input,
+// for the artifacts of a circuit with public inputs.
+func (c *Client) VerifyWithPublicInputs(ctx context.Context, proof []byte, input zkecdsa.Input) error {
+	req := &zkecdsapb.VerifyRequest{Proof: proof}
+	if ecdsa, ok := input.(*zkecdsa.ProveInputEcdsa); ok {
+		req.Against = &zkecdsapb.VerifyRequest_Ecdsa{Ecdsa: zkecdsapb.NewEcdsaInput(ecdsa)}
+	} else {
+		b, err := json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("error encoding input: %w", err)
+		}
+		req.Against = &zkecdsapb.VerifyRequest_InputJson{InputJson: string(b)}
+	}
+	return c.verify(ctx, req)
+}
+
+func (c *Client) verify(ctx context.Context, req *zkecdsapb.VerifyRequest) error {
+	resp, err := c.rpc.Verify(ctx, req)
+	if err != nil {
+		return statusError(err)
+	}
+	if !resp.GetValid() {
+		return &Error{Kind: resp.GetError().GetKind(), Message: resp.GetError().GetMessage()}
+	}
+	return nil
+}
+
+// VerifyingKey returns the verifying key of the server and the backend it is
+// for, to verify proofs locally.
+func (c *Client) VerifyingKey(ctx context.Context) (zkecdsa.VerifyingKey, zkecdsa.Backend, error) {
+	resp, err := c.rpc.GetVerifyingKey(ctx, &zkecdsapb.GetVerifyingKeyRequest{})
+	if err != nil {
+		return nil, "", statusError(err)
+	}
+	vk, err := zkecdsa.UnmarshalVerifyingKey(resp.GetVerifyingKey())
+	if err != nil {
+		return nil, "", err
+	}
+	backend, err := zkecdsa.ParseBackend(resp.GetBackend())
+	if err != nil {
+		return nil, "", err
+	}
+	return vk, backend, nil
+}
+
+// Stream proves a batch of inputs over one ProveStream call.
+type Stream struct {
+	stream zkecdsapb.Prover_ProveStreamClient
+}
+
+// ProveStream opens a stream on which inputs are sent with Send and their
+// results received with Recv, in completion order. The server throttles
+// Send when its queue is full instead of failing.
+func (c *Client) ProveStream(ctx context.Context) (*Stream, error) {
+	stream, err := c.rpc.ProveStream(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &Stream{stream: stream}, nil
+}
+
+// Send sends input to prove; id is given back in its Result.
+func (s *Stream) Send(id string, input zkecdsa.Input) error {
+	req, err := proveRequest(id, input)
+	if err != nil {
+		return err
+	}
+	if err := s.stream.Send(req); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+// CloseSend tells the server that every input was sent. Recv then returns
+// the remaining results, followed by io.EOF.
+func (s *Stream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+// Recv returns the next result. An input that failed is a result with Err
+// set, not an error of Recv.
+func (s *Stream) Recv() (*Result, error) {
+	resp, err := s.stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return result(resp), nil
+}
+
+func proveRequest(id string, input zkecdsa.Input) (*zkecdsapb.ProveRequest, error) {
+	req := &zkecdsapb.ProveRequest{Id: id}
+	if ecdsa, ok := input.(*zkecdsa.ProveInputEcdsa); ok {
+		req.Input = &zkecdsapb.ProveRequest_Ecdsa{Ecdsa: zkecdsapb.NewEcdsaInput(ecdsa)}
+		return req, nil
+	}
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding input: %w", err)
+	}
+	req.Input = &zkecdsapb.ProveRequest_InputJson{InputJson: string(b)}
+	return req, nil
+}
+
+func result(resp *zkecdsapb.ProveResponse) *Result {
+	r := &Result{
+		ID:            resp.GetId(),
+		Proof:         resp.GetProof(),
+		PublicWitness: resp.GetPublicWitness(),
+		ProveTime:     time.Duration(resp.GetProveMs()) * time.Millisecond,
+	}
+	if e := resp.GetError(); e != nil {
+		r.Err = &Error{Kind: e.GetKind(), Message: e.GetMessage()}
+	}
+	return r
+}
+
+// statusError returns the Error carried by the status of err, or err itself
+// for a status without one, such as a transport failure.
+func statusError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, detail := range s.Details() {
+		if e, ok := detail.(*zkecdsapb.Error); ok {
+			return &Error{Kind: e.GetKind(), Message: e.GetMessage()}
+		}
+	}
+	return err
+}
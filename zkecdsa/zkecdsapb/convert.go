@@ -0,0 +1,39 @@
+// Package zkecdsapb holds the protobuf messages and gRPC stubs of the Prover
+// service that zkecdsa serve -grpc-addr implements, generated from
+// zkecdsa.proto, and their conversions to the types of the zkecdsa package.
+// The zkecdsaclient package wraps the stubs in a client of those types.
+package zkecdsapb
+
+// Run through make proto, which puts the pinned plugins on the PATH.
+//
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative zkecdsa.proto
+
+import "github.com/ZKNoxHQ/GnarkPlayground/zkecdsa"
+
+// NewEcdsaInput returns the message of a ProveInputEcdsa.
+func NewEcdsaInput(in *zkecdsa.ProveInputEcdsa) *EcdsaInput {
+	return &EcdsaInput{
+		MsgHash:   in.MsgHash,
+		R:         in.R,
+		S:         in.S,
+		Signature: in.Signature,
+		PubX:      in.PubX,
+		PubY:      in.PubY,
+		PublicKey: in.PublicKey,
+		Curve:     string(in.Curve),
+	}
+}
+
+// ProveInput returns the ProveInputEcdsa of the message.
+func (x *EcdsaInput) ProveInput() *zkecdsa.ProveInputEcdsa {
+	return &zkecdsa.ProveInputEcdsa{
+		MsgHash:   x.GetMsgHash(),
+		R:         x.GetR(),
+		S:         x.GetS(),
+		Signature: x.GetSignature(),
+		PubX:      x.GetPubX(),
+		PubY:      x.GetPubY(),
+		PublicKey: x.GetPublicKey(),
+		Curve:     zkecdsa.Curve(x.GetCurve()),
+	}
+}
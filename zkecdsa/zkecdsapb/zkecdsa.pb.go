@@ -0,0 +1,761 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: zkecdsa.proto
+
+package zkecdsapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EcdsaInput mirrors ProveInputEcdsa: the witness of the ecdsa circuit.
+// Scalars and coordinates are hex strings; give either r and s or the DER
+// signature, and either pub_x and pub_y or public_key.
+type EcdsaInput struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	MsgHash string                 `protobuf:"bytes,1,opt,name=msg_hash,json=msgHash,proto3" json:"msg_hash,omitempty"`
+	R       string                 `protobuf:"bytes,2,opt,name=r,proto3" json:"r,omitempty"`
+	S       string                 `protobuf:"bytes,3,opt,name=s,proto3" json:"s,omitempty"`
+	// ASN.1 DER signature in hex.
+	Signature string `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	PubX      string `protobuf:"bytes,5,opt,name=pub_x,json=pubX,proto3" json:"pub_x,omitempty"`
+	PubY      string `protobuf:"bytes,6,opt,name=pub_y,json=pubY,proto3" json:"pub_y,omitempty"`
+	// SEC1 point in hex, compressed or not, a PKIX PEM block or a JWK.
+	PublicKey string `protobuf:"bytes,7,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// "p256" or "secp256k1"; the curve of the server if empty.
+	Curve         string `protobuf:"bytes,8,opt,name=curve,proto3" json:"curve,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EcdsaInput) Reset() {
+	*x = EcdsaInput{}
+	mi := &file_zkecdsa_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EcdsaInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EcdsaInput) ProtoMessage() {}
+
+func (x *EcdsaInput) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EcdsaInput.ProtoReflect.Descriptor instead.
+func (*EcdsaInput) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EcdsaInput) GetMsgHash() string {
+	if x != nil {
+		return x.MsgHash
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetR() string {
+	if x != nil {
+		return x.R
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetS() string {
+	if x != nil {
+		return x.S
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetPubX() string {
+	if x != nil {
+		return x.PubX
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetPubY() string {
+	if x != nil {
+		return x.PubY
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *EcdsaInput) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+type ProveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Echoed in the response, to match the responses of ProveStream to their
+	// requests.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Input:
+	//
+	//	*ProveRequest_Ecdsa
+	//	*ProveRequest_InputJson
+	Input         isProveRequest_Input `protobuf_oneof:"input"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProveRequest) Reset() {
+	*x = ProveRequest{}
+	mi := &file_zkecdsa_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProveRequest) ProtoMessage() {}
+
+func (x *ProveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProveRequest.ProtoReflect.Descriptor instead.
+func (*ProveRequest) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProveRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProveRequest) GetInput() isProveRequest_Input {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *ProveRequest) GetEcdsa() *EcdsaInput {
+	if x != nil {
+		if x, ok := x.Input.(*ProveRequest_Ecdsa); ok {
+			return x.Ecdsa
+		}
+	}
+	return nil
+}
+
+func (x *ProveRequest) GetInputJson() string {
+	if x != nil {
+		if x, ok := x.Input.(*ProveRequest_InputJson); ok {
+			return x.InputJson
+		}
+	}
+	return ""
+}
+
+type isProveRequest_Input interface {
+	isProveRequest_Input()
+}
+
+type ProveRequest_Ecdsa struct {
+	Ecdsa *EcdsaInput `protobuf:"bytes,2,opt,name=ecdsa,proto3,oneof"`
+}
+
+type ProveRequest_InputJson struct {
+	// Witness input JSON, for the circuits other than ecdsa.
+	InputJson string `protobuf:"bytes,3,opt,name=input_json,json=inputJson,proto3,oneof"`
+}
+
+func (*ProveRequest_Ecdsa) isProveRequest_Input() {}
+
+func (*ProveRequest_InputJson) isProveRequest_Input() {}
+
+type ProveResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Proof, as MarshalProof writes it.
+	Proof []byte `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	// Public witness, as MarshalPublicWitness writes it.
+	PublicWitness []byte `protobuf:"bytes,3,opt,name=public_witness,json=publicWitness,proto3" json:"public_witness,omitempty"`
+	ProveMs       int64  `protobuf:"varint,4,opt,name=prove_ms,json=proveMs,proto3" json:"prove_ms,omitempty"`
+	// ProveStream only: why the input failed, the proof fields being empty.
+	Error         *Error `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProveResponse) Reset() {
+	*x = ProveResponse{}
+	mi := &file_zkecdsa_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProveResponse) ProtoMessage() {}
+
+func (x *ProveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProveResponse.ProtoReflect.Descriptor instead.
+func (*ProveResponse) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProveResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProveResponse) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *ProveResponse) GetPublicWitness() []byte {
+	if x != nil {
+		return x.PublicWitness
+	}
+	return nil
+}
+
+func (x *ProveResponse) GetProveMs() int64 {
+	if x != nil {
+		return x.ProveMs
+	}
+	return 0
+}
+
+func (x *ProveResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type Error struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// signature_invalid, invalid_input, proving_failed, verification_failed,
+	// unavailable or internal.
+	Kind          string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_zkecdsa_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type VerifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Proof, as MarshalProof writes it.
+	Proof []byte `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	// Types that are valid to be assigned to Against:
+	//
+	//	*VerifyRequest_PublicWitness
+	//	*VerifyRequest_Ecdsa
+	//	*VerifyRequest_InputJson
+	Against       isVerifyRequest_Against `protobuf_oneof:"against"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_zkecdsa_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyRequest) GetProof() []byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetAgainst() isVerifyRequest_Against {
+	if x != nil {
+		return x.Against
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetPublicWitness() []byte {
+	if x != nil {
+		if x, ok := x.Against.(*VerifyRequest_PublicWitness); ok {
+			return x.PublicWitness
+		}
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetEcdsa() *EcdsaInput {
+	if x != nil {
+		if x, ok := x.Against.(*VerifyRequest_Ecdsa); ok {
+			return x.Ecdsa
+		}
+	}
+	return nil
+}
+
+func (x *VerifyRequest) GetInputJson() string {
+	if x != nil {
+		if x, ok := x.Against.(*VerifyRequest_InputJson); ok {
+			return x.InputJson
+		}
+	}
+	return ""
+}
+
+type isVerifyRequest_Against interface {
+	isVerifyRequest_Against()
+}
+
+type VerifyRequest_PublicWitness struct {
+	// Public witness, as MarshalPublicWitness writes it.
+	PublicWitness []byte `protobuf:"bytes,2,opt,name=public_witness,json=publicWitness,proto3,oneof"`
+}
+
+type VerifyRequest_Ecdsa struct {
+	// Input of which only the public values are read.
+	Ecdsa *EcdsaInput `protobuf:"bytes,3,opt,name=ecdsa,proto3,oneof"`
+}
+
+type VerifyRequest_InputJson struct {
+	// Witness input JSON of which only the public values are read.
+	InputJson string `protobuf:"bytes,4,opt,name=input_json,json=inputJson,proto3,oneof"`
+}
+
+func (*VerifyRequest_PublicWitness) isVerifyRequest_Against() {}
+
+func (*VerifyRequest_Ecdsa) isVerifyRequest_Against() {}
+
+func (*VerifyRequest_InputJson) isVerifyRequest_Against() {}
+
+type VerifyResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Valid    bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	VerifyMs int64                  `protobuf:"varint,2,opt,name=verify_ms,json=verifyMs,proto3" json:"verify_ms,omitempty"`
+	// Why the proof does not verify, when valid is false.
+	Error         *Error `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_zkecdsa_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetVerifyMs() int64 {
+	if x != nil {
+		return x.VerifyMs
+	}
+	return 0
+}
+
+func (x *VerifyResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type GetVerifyingKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVerifyingKeyRequest) Reset() {
+	*x = GetVerifyingKeyRequest{}
+	mi := &file_zkecdsa_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVerifyingKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVerifyingKeyRequest) ProtoMessage() {}
+
+func (x *GetVerifyingKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVerifyingKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetVerifyingKeyRequest) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{6}
+}
+
+type GetVerifyingKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Verifying key, as verifying_key.bin holds it.
+	VerifyingKey  []byte `protobuf:"bytes,1,opt,name=verifying_key,json=verifyingKey,proto3" json:"verifying_key,omitempty"`
+	Circuit       string `protobuf:"bytes,2,opt,name=circuit,proto3" json:"circuit,omitempty"`
+	Curve         string `protobuf:"bytes,3,opt,name=curve,proto3" json:"curve,omitempty"`
+	Backend       string `protobuf:"bytes,4,opt,name=backend,proto3" json:"backend,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVerifyingKeyResponse) Reset() {
+	*x = GetVerifyingKeyResponse{}
+	mi := &file_zkecdsa_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVerifyingKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVerifyingKeyResponse) ProtoMessage() {}
+
+func (x *GetVerifyingKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_zkecdsa_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVerifyingKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetVerifyingKeyResponse) Descriptor() ([]byte, []int) {
+	return file_zkecdsa_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetVerifyingKeyResponse) GetVerifyingKey() []byte {
+	if x != nil {
+		return x.VerifyingKey
+	}
+	return nil
+}
+
+func (x *GetVerifyingKeyResponse) GetCircuit() string {
+	if x != nil {
+		return x.Circuit
+	}
+	return ""
+}
+
+func (x *GetVerifyingKeyResponse) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+func (x *GetVerifyingKeyResponse) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+var File_zkecdsa_proto protoreflect.FileDescriptor
+
+const file_zkecdsa_proto_rawDesc = "" +
+	"\n" +
+	"\rzkecdsa.proto\x12\n" +
+	"zkecdsa.v1\"\xc0\x01\n" +
+	"\n" +
+	"EcdsaInput\x12\x19\n" +
+	"\bmsg_hash\x18\x01 \x01(\tR\amsgHash\x12\f\n" +
+	"\x01r\x18\x02 \x01(\tR\x01r\x12\f\n" +
+	"\x01s\x18\x03 \x01(\tR\x01s\x12\x1c\n" +
+	"\tsignature\x18\x04 \x01(\tR\tsignature\x12\x13\n" +
+	"\x05pub_x\x18\x05 \x01(\tR\x04pubX\x12\x13\n" +
+	"\x05pub_y\x18\x06 \x01(\tR\x04pubY\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\a \x01(\tR\tpublicKey\x12\x14\n" +
+	"\x05curve\x18\b \x01(\tR\x05curve\"x\n" +
+	"\fProveRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x05ecdsa\x18\x02 \x01(\v2\x16.zkecdsa.v1.EcdsaInputH\x00R\x05ecdsa\x12\x1f\n" +
+	"\n" +
+	"input_json\x18\x03 \x01(\tH\x00R\tinputJsonB\a\n" +
+	"\x05input\"\xa0\x01\n" +
+	"\rProveResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05proof\x18\x02 \x01(\fR\x05proof\x12%\n" +
+	"\x0epublic_witness\x18\x03 \x01(\fR\rpublicWitness\x12\x19\n" +
+	"\bprove_ms\x18\x04 \x01(\x03R\aproveMs\x12'\n" +
+	"\x05error\x18\x05 \x01(\v2\x11.zkecdsa.v1.ErrorR\x05error\"5\n" +
+	"\x05Error\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\"\xaa\x01\n" +
+	"\rVerifyRequest\x12\x14\n" +
+	"\x05proof\x18\x01 \x01(\fR\x05proof\x12'\n" +
+	"\x0epublic_witness\x18\x02 \x01(\fH\x00R\rpublicWitness\x12.\n" +
+	"\x05ecdsa\x18\x03 \x01(\v2\x16.zkecdsa.v1.EcdsaInputH\x00R\x05ecdsa\x12\x1f\n" +
+	"\n" +
+	"input_json\x18\x04 \x01(\tH\x00R\tinputJsonB\t\n" +
+	"\aagainst\"l\n" +
+	"\x0eVerifyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1b\n" +
+	"\tverify_ms\x18\x02 \x01(\x03R\bverifyMs\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x11.zkecdsa.v1.ErrorR\x05error\"\x18\n" +
+	"\x16GetVerifyingKeyRequest\"\x88\x01\n" +
+	"\x17GetVerifyingKeyResponse\x12#\n" +
+	"\rverifying_key\x18\x01 \x01(\fR\fverifyingKey\x12\x18\n" +
+	"\acircuit\x18\x02 \x01(\tR\acircuit\x12\x14\n" +
+	"\x05curve\x18\x03 \x01(\tR\x05curve\x12\x18\n" +
+	"\abackend\x18\x04 \x01(\tR\abackend2\xab\x02\n" +
+	"\x06Prover\x12<\n" +
+	"\x05Prove\x12\x18.zkecdsa.v1.ProveRequest\x1a\x19.zkecdsa.v1.ProveResponse\x12?\n" +
+	"\x06Verify\x12\x19.zkecdsa.v1.VerifyRequest\x1a\x1a.zkecdsa.v1.VerifyResponse\x12F\n" +
+	"\vProveStream\x12\x18.zkecdsa.v1.ProveRequest\x1a\x19.zkecdsa.v1.ProveResponse(\x010\x01\x12Z\n" +
+	"\x0fGetVerifyingKey\x12\".zkecdsa.v1.GetVerifyingKeyRequest\x1a#.zkecdsa.v1.GetVerifyingKeyResponseB6Z4github.com/ZKNoxHQ/GnarkPlayground/zkecdsa/zkecdsapbb\x06proto3"
+
+var (
+	file_zkecdsa_proto_rawDescOnce sync.Once
+	file_zkecdsa_proto_rawDescData []byte
+)
+
+func file_zkecdsa_proto_rawDescGZIP() []byte {
+	file_zkecdsa_proto_rawDescOnce.Do(func() {
+		file_zkecdsa_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_zkecdsa_proto_rawDesc), len(file_zkecdsa_proto_rawDesc)))
+	})
+	return file_zkecdsa_proto_rawDescData
+}
+
+var file_zkecdsa_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_zkecdsa_proto_goTypes = []any{
+	(*EcdsaInput)(nil),              // 0: zkecdsa.v1.EcdsaInput
+	(*ProveRequest)(nil),            // 1: zkecdsa.v1.ProveRequest
+	(*ProveResponse)(nil),           // 2: zkecdsa.v1.ProveResponse
+	(*Error)(nil),                   // 3: zkecdsa.v1.Error
+	(*VerifyRequest)(nil),           // 4: zkecdsa.v1.VerifyRequest
+	(*VerifyResponse)(nil),          // 5: zkecdsa.v1.VerifyResponse
+	(*GetVerifyingKeyRequest)(nil),  // 6: zkecdsa.v1.GetVerifyingKeyRequest
+	(*GetVerifyingKeyResponse)(nil), // 7: zkecdsa.v1.GetVerifyingKeyResponse
+}
+var file_zkecdsa_proto_depIdxs = []int32{
+	0, // 0: zkecdsa.v1.ProveRequest.ecdsa:type_name -> zkecdsa.v1.EcdsaInput
+	3, // 1: zkecdsa.v1.ProveResponse.error:type_name -> zkecdsa.v1.Error
+	0, // 2: zkecdsa.v1.VerifyRequest.ecdsa:type_name -> zkecdsa.v1.EcdsaInput
+	3, // 3: zkecdsa.v1.VerifyResponse.error:type_name -> zkecdsa.v1.Error
+	1, // 4: zkecdsa.v1.Prover.Prove:input_type -> zkecdsa.v1.ProveRequest
+	4, // 5: zkecdsa.v1.Prover.Verify:input_type -> zkecdsa.v1.VerifyRequest
+	1, // 6: zkecdsa.v1.Prover.ProveStream:input_type -> zkecdsa.v1.ProveRequest
+	6, // 7: zkecdsa.v1.Prover.GetVerifyingKey:input_type -> zkecdsa.v1.GetVerifyingKeyRequest
+	2, // 8: zkecdsa.v1.Prover.Prove:output_type -> zkecdsa.v1.ProveResponse
+	5, // 9: zkecdsa.v1.Prover.Verify:output_type -> zkecdsa.v1.VerifyResponse
+	2, // 10: zkecdsa.v1.Prover.ProveStream:output_type -> zkecdsa.v1.ProveResponse
+	7, // 11: zkecdsa.v1.Prover.GetVerifyingKey:output_type -> zkecdsa.v1.GetVerifyingKeyResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_zkecdsa_proto_init() }
+func file_zkecdsa_proto_init() {
+	if File_zkecdsa_proto != nil {
+		return
+	}
+	file_zkecdsa_proto_msgTypes[1].OneofWrappers = []any{
+		(*ProveRequest_Ecdsa)(nil),
+		(*ProveRequest_InputJson)(nil),
+	}
+	file_zkecdsa_proto_msgTypes[4].OneofWrappers = []any{
+		(*VerifyRequest_PublicWitness)(nil),
+		(*VerifyRequest_Ecdsa)(nil),
+		(*VerifyRequest_InputJson)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_zkecdsa_proto_rawDesc), len(file_zkecdsa_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_zkecdsa_proto_goTypes,
+		DependencyIndexes: file_zkecdsa_proto_depIdxs,
+		MessageInfos:      file_zkecdsa_proto_msgTypes,
+	}.Build()
+	File_zkecdsa_proto = out.File
+	file_zkecdsa_proto_goTypes = nil
+	file_zkecdsa_proto_depIdxs = nil
+}
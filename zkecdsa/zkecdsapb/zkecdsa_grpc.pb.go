@@ -0,0 +1,262 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: zkecdsa.proto
+
+package zkecdsapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Prover_Prove_FullMethodName           = "/zkecdsa.v1.Prover/Prove"
+	Prover_Verify_FullMethodName          = "/zkecdsa.v1.Prover/Verify"
+	Prover_ProveStream_FullMethodName     = "/zkecdsa.v1.Prover/ProveStream"
+	Prover_GetVerifyingKey_FullMethodName = "/zkecdsa.v1.Prover/GetVerifyingKey"
+)
+
+// ProverClient is the client API for Prover service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Prover proves and verifies with the artifacts of one circuit, curve and
+// backend, loaded once by the server. Failures of unary calls are reported
+// with a status code: INVALID_ARGUMENT for an input the prover rejects,
+// RESOURCE_EXHAUSTED when every proof slot and queue slot is taken,
+// UNAVAILABLE while shutting down. The error kind of the command-line tool
+// is attached as an Error detail of the status.
+type ProverClient interface {
+	// Prove proves one input, returning once the proof is done.
+	Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProveResponse, error)
+	// Verify verifies a proof. A proof that does not verify is a successful
+	// call with valid false.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// ProveStream proves every input sent on the stream, with the server's
+	// concurrency, and answers each with a response carrying the id of its
+	// request, in completion order. A failed input is answered with an error
+	// and does not end the stream.
+	ProveStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProveRequest, ProveResponse], error)
+	// GetVerifyingKey returns the verifying key of the artifacts, for
+	// verifying proofs without the server.
+	GetVerifyingKey(ctx context.Context, in *GetVerifyingKeyRequest, opts ...grpc.CallOption) (*GetVerifyingKeyResponse, error)
+}
+
+type proverClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProverClient(cc grpc.ClientConnInterface) ProverClient {
+	return &proverClient{cc}
+}
+
+func (c *proverClient) Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProveResponse)
+	err := c.cc.Invoke(ctx, Prover_Prove_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proverClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, Prover_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proverClient) ProveStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProveRequest, ProveResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Prover_ServiceDesc.Streams[0], Prover_ProveStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProveRequest, ProveResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Prover_ProveStreamClient = grpc.BidiStreamingClient[ProveRequest, ProveResponse]
+
+func (c *proverClient) GetVerifyingKey(ctx context.Context, in *GetVerifyingKeyRequest, opts ...grpc.CallOption) (*GetVerifyingKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVerifyingKeyResponse)
+	err := c.cc.Invoke(ctx, Prover_GetVerifyingKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProverServer is the server API for Prover service.
+// All implementations must embed UnimplementedProverServer
+// for forward compatibility.
+//
+// Prover proves and verifies with the artifacts of one circuit, curve and
+// backend, loaded once by the server. Failures of unary calls are reported
+// with a status code: INVALID_ARGUMENT for an input the prover rejects,
+// RESOURCE_EXHAUSTED when every proof slot and queue slot is taken,
+// UNAVAILABLE while shutting down. The error kind of the command-line tool
+// is attached as an Error detail of the status.
+type ProverServer interface {
+	// Prove proves one input, returning once the proof is done.
+	Prove(context.Context, *ProveRequest) (*ProveResponse, error)
+	// Verify verifies a proof. A proof that does not verify is a successful
+	// call with valid false.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// ProveStream proves every input sent on the stream, with the server's
+	// concurrency, and answers each with a response carrying the id of its
+	// request, in completion order. A failed input is answered with an error
+	// and does not end the stream.
+	ProveStream(grpc.BidiStreamingServer[ProveRequest, ProveResponse]) error
+	// GetVerifyingKey returns the verifying key of the artifacts, for
+	// verifying proofs without the server.
+	GetVerifyingKey(context.Context, *GetVerifyingKeyRequest) (*GetVerifyingKeyResponse, error)
+	mustEmbedUnimplementedProverServer()
+}
+
+// UnimplementedProverServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProverServer struct{}
+
+func (UnimplementedProverServer) Prove(context.Context, *ProveRequest) (*ProveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prove not implemented")
+}
+func (UnimplementedProverServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedProverServer) ProveStream(grpc.BidiStreamingServer[ProveRequest, ProveResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ProveStream not implemented")
+}
+func (UnimplementedProverServer) GetVerifyingKey(context.Context, *GetVerifyingKeyRequest) (*GetVerifyingKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVerifyingKey not implemented")
+}
+func (UnimplementedProverServer) mustEmbedUnimplementedProverServer() {}
+func (UnimplementedProverServer) testEmbeddedByValue()                {}
+
+// UnsafeProverServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProverServer will
+// result in compilation errors.
+type UnsafeProverServer interface {
+	mustEmbedUnimplementedProverServer()
+}
+
+func RegisterProverServer(s grpc.ServiceRegistrar, srv ProverServer) {
+	// If the following call pancis, it indicates UnimplementedProverServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Prover_ServiceDesc, srv)
+}
+
+func _Prover_Prove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProverServer).Prove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Prover_Prove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProverServer).Prove(ctx, req.(*ProveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Prover_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProverServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Prover_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProverServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Prover_ProveStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ProverServer).ProveStream(&grpc.GenericServerStream[ProveRequest, ProveResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Prover_ProveStreamServer = grpc.BidiStreamingServer[ProveRequest, ProveResponse]
+
+func _Prover_GetVerifyingKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVerifyingKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProverServer).GetVerifyingKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Prover_GetVerifyingKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProverServer).GetVerifyingKey(ctx, req.(*GetVerifyingKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Prover_ServiceDesc is the grpc.ServiceDesc for Prover service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Prover_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zkecdsa.v1.Prover",
+	HandlerType: (*ProverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Prove",
+			Handler:    _Prover_Prove_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _Prover_Verify_Handler,
+		},
+		{
+			MethodName: "GetVerifyingKey",
+			Handler:    _Prover_GetVerifyingKey_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ProveStream",
+			Handler:       _Prover_ProveStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "zkecdsa.proto",
+}